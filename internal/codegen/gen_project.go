@@ -100,3 +100,35 @@ func genVolumes(project *types.Project) []jen.Code {
 	}
 	return stmts
 }
+
+// genConfigs returns project.WithConfig(...) statements for each config.
+func genConfigs(project *types.Project) []jen.Code {
+	var stmts []jen.Code
+	names := make([]string, 0, len(project.Configs))
+	for name := range project.Configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg := project.Configs[name]
+		args := []jen.Code{jen.Lit(name)}
+		if cfg.Name != "" && cfg.Name != name {
+			args = append(args, jen.Qual(pkgProjectConfig, "WithName").Call(jen.Lit(cfg.Name)))
+		}
+		switch {
+		case bool(cfg.External):
+			args = append(args, jen.Qual(pkgProjectConfig, "WithExternal").Call())
+		case cfg.Environment != "":
+			args = append(args, jen.Qual(pkgProjectConfig, "WithEnvironment").Call(jen.Lit(cfg.Environment)))
+		case cfg.File != "":
+			args = append(args, jen.Qual(pkgProjectConfig, "WithFile").Call(jen.Lit(cfg.File)))
+		case cfg.Content != "":
+			args = append(args, jen.Qual(pkgProjectConfig, "WithContent").Call(jen.Lit(cfg.Content)))
+		}
+		if cfg.TemplateDriver != "" {
+			args = append(args, jen.Qual(pkgProjectConfig, "WithTemplateDriver").Call(jen.Lit(cfg.TemplateDriver)))
+		}
+		stmts = append(stmts, jen.Id("project").Dot("WithConfig").Call(args...))
+	}
+	return stmts
+}
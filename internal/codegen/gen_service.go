@@ -566,6 +566,24 @@ func genServiceLevelConfig(svc *types.ServiceConfig) []jen.Code {
 		}
 		parts = append(parts, jen.Qual(pkgSC, "WithSecret").Call(secretParts...))
 	}
+	for _, cfg := range svc.Configs {
+		configParts := []jen.Code{
+			jen.Qual(pkgConfigSvc, "WithSource").Call(jen.Lit(cfg.Source)),
+		}
+		if cfg.Target != "" {
+			configParts = append(configParts, jen.Qual(pkgConfigSvc, "WithTarget").Call(jen.Lit(cfg.Target)))
+		}
+		if cfg.UID != "" {
+			configParts = append(configParts, jen.Qual(pkgConfigSvc, "WithUID").Call(jen.Lit(cfg.UID)))
+		}
+		if cfg.GID != "" {
+			configParts = append(configParts, jen.Qual(pkgConfigSvc, "WithGID").Call(jen.Lit(cfg.GID)))
+		}
+		if cfg.Mode != nil {
+			configParts = append(configParts, jen.Qual(pkgConfigSvc, "WithMode").Call(jen.Lit(int(*cfg.Mode))))
+		}
+		parts = append(parts, jen.Qual(pkgSC, "WithConfig").Call(configParts...))
+	}
 	if svc.Build != nil {
 		buildParts := genBuild(svc.Build)
 		if len(buildParts) > 0 {
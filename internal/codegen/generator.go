@@ -26,6 +26,8 @@ const (
 	pkgResource   = "github.com/aptd3v/go-contain/pkg/create/config/sc/deploy/resource"
 	pkgDevice     = "github.com/aptd3v/go-contain/pkg/create/config/sc/deploy/resource/device"
 	pkgSecretSvc  = "github.com/aptd3v/go-contain/pkg/create/config/sc/secrets/secretservice"
+	pkgConfigSvc  = "github.com/aptd3v/go-contain/pkg/create/config/sc/configs/configservice"
+	pkgProjectConfig = "github.com/aptd3v/go-contain/pkg/create/config/sc/configs/projectconfig"
 	pkgNetwork  = "github.com/aptd3v/go-contain/pkg/create/config/sc/network"
 	pkgPool     = "github.com/aptd3v/go-contain/pkg/create/config/sc/network/pool"
 	pkgVolume   = "github.com/aptd3v/go-contain/pkg/create/config/sc/volume"
@@ -65,6 +67,11 @@ func Generate(project *types.Project, opts Options) ([]byte, error) {
 		body = append(body, jen.Line(), jen.Comment("// Volumes"))
 		body = append(body, volumeStmts...)
 	}
+	configStmts := genConfigs(project)
+	if len(configStmts) > 0 {
+		body = append(body, jen.Line(), jen.Comment("// Configs"))
+		body = append(body, configStmts...)
+	}
 	serviceNames := make([]string, 0, len(project.Services))
 	for name := range project.Services {
 		serviceNames = append(serviceNames, name)
@@ -156,7 +163,7 @@ func Generate(project *types.Project, opts Options) ([]byte, error) {
 	// Break long container/service chains so each .With* starts on its own line.
 	out = bytes.ReplaceAll(out, []byte(").With"), []byte(").\n\t\tWith"))
 	// Put each With* argument on its own line (including nested calls like deploy.WithRollbackConfig(update.With...)).
-	pkgs := []string{"cc.", "hc.", "nc.", "sc.", "health.", "network.", "build.", "deploy.", "endpoint.", "resource.", "ipam.", "update.", "device.", "secretservice.", "ulimit."}
+	pkgs := []string{"cc.", "hc.", "nc.", "sc.", "health.", "network.", "build.", "deploy.", "endpoint.", "resource.", "ipam.", "update.", "device.", "secretservice.", "configservice.", "ulimit."}
 	for _, pkg := range pkgs {
 		for n := 1; n <= 6; n++ {
 			old := append(bytes.Repeat([]byte(")"), n), []byte(", "+pkg)...)
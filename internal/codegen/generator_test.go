@@ -164,6 +164,11 @@ func TestGenerate_e2eRealComposeFile(t *testing.T) {
 		"hc.WithRWNamedVolumeMount",
 		"network.WithDriver",
 		"deploy.WithReplicas",
+		"project.WithConfig",
+		"projectconfig.WithContent",
+		"projectconfig.WithFile",
+		"sc.WithConfig",
+		"configservice.WithTarget",
 	} {
 		if !strings.Contains(s, substr) {
 			t.Errorf("e2e generated code missing %q", substr)
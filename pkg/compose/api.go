@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 )
 
 // ComposeUpOptions is the options for the compose up command
@@ -38,6 +39,9 @@ type ComposeUpOptions struct {
 	Watch                   bool
 	Yes                     bool
 
+	// Progress receives parsed progress events, see up.WithProgress
+	Progress ProgressFunc
+
 	Profiles []string
 	Flags    []string
 	Writer   io.Writer
@@ -203,6 +207,7 @@ type ComposeLogsOptions struct {
 	Tail        *int
 	Follow      bool
 	NoLogPrefix bool
+	Timestamps  bool
 
 	Writer   io.Writer
 	Flags    []string
@@ -220,6 +225,9 @@ func (opt *ComposeLogsOptions) GenerateFlags() ([]string, error) {
 	if opt.NoLogPrefix {
 		flags = append(flags, "--no-log-prefix")
 	}
+	if opt.Timestamps {
+		flags = append(flags, "--timestamps")
+	}
 	return flags, nil
 }
 
@@ -356,9 +364,9 @@ func (opt *ComposeRestartOptions) GenerateFlags() ([]string, error) {
 // ComposeBuildOptions is the options for the compose build command
 type ComposeBuildOptions struct {
 	BuildArg         []string // key=value, appended as --build-arg each
-	Builder           string
+	Builder          string
 	Check            bool
-	Memory           string   // e.g. "2G"
+	Memory           string // e.g. "2G"
 	NoCache          bool
 	Print            bool
 	Provenance       bool
@@ -368,6 +376,7 @@ type ComposeBuildOptions struct {
 	SBOM             bool
 	SSH              []string // e.g. "default" or "key=path"
 	WithDependencies bool
+	Progress         ProgressFunc // receives parsed progress events, see build.WithProgress
 	Writer           io.Writer
 	Profiles         []string
 	ServiceNames     []string
@@ -420,15 +429,16 @@ func (opt *ComposeBuildOptions) GenerateFlags() ([]string, error) {
 
 // ComposePullOptions is the options for the compose pull command
 type ComposePullOptions struct {
-	IgnoreBuildable     bool
-	IgnorePullFailures  bool
-	IncludeDeps         bool
-	Policy              string   // e.g. "missing", "always", "never"
-	Quiet               bool
-	Writer              io.Writer
-	Profiles            []string
-	ServiceNames        []string
-	Flags               []string
+	IgnoreBuildable    bool
+	IgnorePullFailures bool
+	IncludeDeps        bool
+	Policy             string // e.g. "missing", "always", "never"
+	Quiet              bool
+	Progress           ProgressFunc // receives parsed progress events, see pull.WithProgress
+	Writer             io.Writer
+	Profiles           []string
+	ServiceNames       []string
+	Flags              []string
 }
 
 func (opt *ComposePullOptions) GenerateFlags() ([]string, error) {
@@ -455,18 +465,18 @@ func (opt *ComposePullOptions) GenerateFlags() ([]string, error) {
 // Usage: docker compose exec [OPTIONS] SERVICE COMMAND [ARGS...]
 // Service and Command are required; set via WithService and WithCommand.
 type ComposeExecOptions struct {
-	Detach    bool
-	Env       []string // key=value, passed as -e each
-	Index     *int     // --index for multi-replica services
-	NoTTY     bool     // -T: disable TTY
+	Detach     bool
+	Env        []string // key=value, passed as -e each
+	Index      *int     // --index for multi-replica services
+	NoTTY      bool     // -T: disable TTY
 	Privileged bool
-	User      string   // -u
-	Workdir   string   // -w
-	Writer    io.Writer
-	Stdin     io.Reader // optional; forwarded to the container after compose file is read
-	Profiles  []string
-	Service   string   // required: service name
-	Command   []string  // required: command and args (e.g. ["sh", "-c", "echo hi"])
+	User       string // -u
+	Workdir    string // -w
+	Writer     io.Writer
+	Stdin      io.Reader // optional; forwarded to the container after compose file is read
+	Profiles   []string
+	Service    string   // required: service name
+	Command    []string // required: command and args (e.g. ["sh", "-c", "echo hi"])
 }
 
 func (opt *ComposeExecOptions) GenerateFlags() ([]string, error) {
@@ -494,3 +504,690 @@ func (opt *ComposeExecOptions) GenerateFlags() ([]string, error) {
 	}
 	return flags, nil
 }
+
+// ComposeRunOptions is the options for the compose run command.
+// Usage: docker compose run [OPTIONS] SERVICE [COMMAND] [ARGS...]
+// Service is required; set via WithService.
+type ComposeRunOptions struct {
+	Detach       bool
+	Entrypoint   *string
+	Env          []string // key=value, passed as --env each
+	Label        []string // key=value, passed as --label each
+	Name         string
+	NoDeps       bool
+	NoTTY        bool // -T: disable TTY
+	Publish      []string
+	Remove       bool // --rm
+	ServicePorts bool
+	User         string
+	Volumes      []string
+	Workdir      string
+	Writer       io.Writer
+	Stdin        io.Reader // optional; forwarded to the container after compose file is read
+	Profiles     []string
+	Service      string   // required: service name
+	Command      []string // optional: command and args overriding the service command
+	Errs         []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeRunOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose run command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"run", "--rm", "--env", "KEY=value"}
+func (opt *ComposeRunOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"run"}
+	if opt.Detach {
+		flags = append(flags, "--detach")
+	}
+	if opt.Entrypoint != nil {
+		flags = append(flags, "--entrypoint", *opt.Entrypoint)
+	}
+	for _, e := range opt.Env {
+		flags = append(flags, "--env", e)
+	}
+	for _, l := range opt.Label {
+		flags = append(flags, "--label", l)
+	}
+	if opt.Name != "" {
+		flags = append(flags, "--name", opt.Name)
+	}
+	if opt.NoDeps {
+		flags = append(flags, "--no-deps")
+	}
+	if opt.NoTTY {
+		flags = append(flags, "--no-TTY")
+	}
+	for _, p := range opt.Publish {
+		flags = append(flags, "--publish", p)
+	}
+	if opt.Remove {
+		flags = append(flags, "--rm")
+	}
+	if opt.ServicePorts {
+		opt.addErrorWhen(len(opt.Publish) > 0, "--service-ports", "WithServicePorts and WithPublish cannot be used together")
+		flags = append(flags, "--service-ports")
+	}
+	if opt.User != "" {
+		flags = append(flags, "--user", opt.User)
+	}
+	for _, v := range opt.Volumes {
+		flags = append(flags, "--volume", v)
+	}
+	if opt.Workdir != "" {
+		flags = append(flags, "--workdir", opt.Workdir)
+	}
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	return flags, nil
+}
+
+// ComposeCreateOptions is the options for the compose create command
+type ComposeCreateOptions struct {
+	Build         bool
+	NoBuild       bool
+	ForceRecreate bool
+	NoRecreate    bool
+	Pull          *string
+	QuietPull     bool
+	RemoveOrphans bool
+	Scale         []ComposeUpScale
+	Yes           bool
+
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+	Errs         []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeCreateOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose create command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"create", "--build", "--force-recreate"}
+func (opt *ComposeCreateOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"create"}
+	if opt.Build {
+		opt.addErrorWhen(opt.NoBuild, "--build", "WithBuild and WithNoBuild cannot be used together")
+		flags = append(flags, "--build")
+	}
+	if opt.NoBuild {
+		flags = append(flags, "--no-build")
+	}
+	if opt.ForceRecreate {
+		opt.addErrorWhen(opt.NoRecreate, "--force-recreate", "WithForceRecreate and WithNoRecreate cannot be used together")
+		flags = append(flags, "--force-recreate")
+	}
+	if opt.NoRecreate {
+		flags = append(flags, "--no-recreate")
+	}
+	if opt.Pull != nil {
+		flags = append(flags, "--pull", *opt.Pull)
+	}
+	if opt.QuietPull {
+		flags = append(flags, "--quiet-pull")
+	}
+	if opt.RemoveOrphans {
+		flags = append(flags, "--remove-orphans")
+	}
+	for _, scale := range opt.Scale {
+		opt.addErrorWhen(scale.Service == "" || scale.Num < 0, "--scale", "Service name required and scale must be non-negative")
+		flags = append(flags, "--scale", fmt.Sprintf("%s=%d", scale.Service, scale.Num))
+	}
+	if opt.Yes {
+		flags = append(flags, "--yes")
+	}
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	return flags, nil
+}
+
+// ComposeRmOptions is the options for the compose rm command
+type ComposeRmOptions struct {
+	// Force is always set by GenerateFlags, compose would read the confirmation from stdin which carries the compose file
+	Force   bool
+	Stop    bool
+	Volumes bool
+
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+// GenerateFlags generates the flags for the compose rm command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"rm", "--force", "--stop"}
+func (opt *ComposeRmOptions) GenerateFlags() ([]string, error) {
+	flags := []string{"rm", "--force"}
+	if opt.Stop {
+		flags = append(flags, "--stop")
+	}
+	if opt.Volumes {
+		flags = append(flags, "--volumes")
+	}
+	return flags, nil
+}
+
+// ComposePauseOptions is the options for the compose pause command
+type ComposePauseOptions struct {
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+func (opt *ComposePauseOptions) GenerateFlags() ([]string, error) {
+	return []string{"pause"}, nil
+}
+
+// ComposeUnpauseOptions is the options for the compose unpause command
+type ComposeUnpauseOptions struct {
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+func (opt *ComposeUnpauseOptions) GenerateFlags() ([]string, error) {
+	return []string{"unpause"}, nil
+}
+
+// ComposeWaitOptions is the options for the compose wait command.
+// Usage: docker compose wait SERVICE [SERVICE...] [OPTIONS]
+// At least one service is required; set via WithServiceNames.
+type ComposeWaitOptions struct {
+	DownProject bool
+
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+// GenerateFlags generates the flags for the compose wait command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"wait", "--down-project"}
+func (opt *ComposeWaitOptions) GenerateFlags() ([]string, error) {
+	if len(opt.ServiceNames) == 0 {
+		return nil, NewComposeFlagError("SERVICE", "at least one service is required, set via WithServiceNames")
+	}
+	flags := []string{"wait"}
+	if opt.DownProject {
+		flags = append(flags, "--down-project")
+	}
+	return flags, nil
+}
+
+// ComposeCpOptions is the options for the compose cp command.
+// Usage: docker compose cp [OPTIONS] SERVICE:SRC_PATH DEST_PATH|-
+//
+//	docker compose cp [OPTIONS] SRC_PATH|- SERVICE:DEST_PATH
+//
+// Source and Destination are required; set via WithFrom or WithTo.
+type ComposeCpOptions struct {
+	Archive     bool
+	FollowLink  bool
+	Index       *int
+	Source      string
+	Destination string
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeCpOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose cp command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"cp", "--archive", "web:/etc/nginx/nginx.conf", "./nginx.conf"}
+func (opt *ComposeCpOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"cp"}
+	if opt.Archive {
+		flags = append(flags, "--archive")
+	}
+	if opt.FollowLink {
+		flags = append(flags, "--follow-link")
+	}
+	if opt.Index != nil {
+		opt.addErrorWhen(*opt.Index < 1, "--index", "index must be greater than 0")
+		flags = append(flags, "--index", strconv.Itoa(*opt.Index))
+	}
+	opt.addErrorWhen(opt.Source == "" || opt.Destination == "", "SRC_PATH", "source and destination are required, set via WithFrom or WithTo")
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	flags = append(flags, opt.Source, opt.Destination)
+	return flags, nil
+}
+
+// ComposeTopOptions is the options for the compose top command
+type ComposeTopOptions struct {
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+func (opt *ComposeTopOptions) GenerateFlags() ([]string, error) {
+	return []string{"top"}, nil
+}
+
+// ComposePortOptions is the options for the compose port command.
+// Usage: docker compose port [OPTIONS] SERVICE PRIVATE_PORT
+// Service and PrivatePort are required; set via WithService and WithPrivatePort.
+type ComposePortOptions struct {
+	Index       *int
+	Protocol    string // "tcp" or "udp"
+	Service     string
+	PrivatePort int
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposePortOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose port command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"port", "--protocol", "udp", "web", "53"}
+func (opt *ComposePortOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"port"}
+	if opt.Index != nil {
+		opt.addErrorWhen(*opt.Index < 1, "--index", "index must be greater than 0")
+		flags = append(flags, "--index", strconv.Itoa(*opt.Index))
+	}
+	if opt.Protocol != "" {
+		opt.addErrorWhen(opt.Protocol != "tcp" && opt.Protocol != "udp", "--protocol", "protocol must be tcp or udp")
+		flags = append(flags, "--protocol", opt.Protocol)
+	}
+	opt.addErrorWhen(opt.Service == "", "SERVICE", "service is required, set via WithService")
+	opt.addErrorWhen(opt.PrivatePort < 1 || opt.PrivatePort > 65535, "PRIVATE_PORT", "private port must be between 1 and 65535")
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	flags = append(flags, opt.Service, strconv.Itoa(opt.PrivatePort))
+	return flags, nil
+}
+
+// ComposeConfigOptions is the options for the compose config command
+type ComposeConfigOptions struct {
+	Format              string   // "yaml" or "json"
+	Hash                []string // --hash, nil = unset, "*" for all services
+	Images              bool
+	NoInterpolate       bool
+	ResolveImageDigests bool
+	Services            bool
+	Volumes             bool
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeConfigOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// listing reports whether a listing option is set, in which case compose prints a list instead of the model
+func (opt *ComposeConfigOptions) listing() bool {
+	return opt.Services || opt.Volumes || opt.Images || opt.Hash != nil
+}
+
+// GenerateFlags generates the flags for the compose config command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"config", "--format", "json", "--no-interpolate"}
+func (opt *ComposeConfigOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"config"}
+	if opt.Format != "" {
+		opt.addErrorWhen(opt.Format != "yaml" && opt.Format != "json", "--format", "format must be yaml or json")
+		flags = append(flags, "--format", opt.Format)
+	}
+	listings := 0
+	if opt.Hash != nil {
+		listings++
+		hash := strings.Join(opt.Hash, ",")
+		if hash == "" {
+			hash = "*"
+		}
+		flags = append(flags, "--hash", hash)
+	}
+	if opt.Images {
+		listings++
+		flags = append(flags, "--images")
+	}
+	if opt.NoInterpolate {
+		flags = append(flags, "--no-interpolate")
+	}
+	if opt.ResolveImageDigests {
+		flags = append(flags, "--resolve-image-digests")
+	}
+	if opt.Services {
+		listings++
+		flags = append(flags, "--services")
+	}
+	if opt.Volumes {
+		listings++
+		flags = append(flags, "--volumes")
+	}
+	opt.addErrorWhen(listings > 1, "--services", "WithServices, WithVolumes, WithImages and WithHash cannot be used together")
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	return flags, nil
+}
+
+// ComposeLsOptions is the options for the compose ls command
+type ComposeLsOptions struct {
+	All    bool
+	Filter []string // e.g. "name=demo"
+
+	Writer io.Writer
+}
+
+// GenerateFlags generates the flags for the compose ls command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"ls", "--format", "json", "--all"}
+func (opt *ComposeLsOptions) GenerateFlags() ([]string, error) {
+	flags := []string{"ls", "--format", "json"}
+	if opt.All {
+		flags = append(flags, "--all")
+	}
+	for _, f := range opt.Filter {
+		flags = append(flags, "--filter", f)
+	}
+	return flags, nil
+}
+
+// ComposeImagesOptions is the options for the compose images command
+type ComposeImagesOptions struct {
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+// GenerateFlags generates the flags for the compose images command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"images", "--format", "json"}
+func (opt *ComposeImagesOptions) GenerateFlags() ([]string, error) {
+	return []string{"images", "--format", "json"}, nil
+}
+
+// ComposeStatsOptions is the options for the compose stats command
+type ComposeStatsOptions struct {
+	All      bool
+	NoStream bool
+	NoTrunc  bool
+	Service  string // optional: only stream stats of this service
+
+	Writer   io.Writer
+	Profiles []string
+}
+
+// GenerateFlags generates the flags for the compose stats command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"stats", "--format", "json", "--no-stream"}
+func (opt *ComposeStatsOptions) GenerateFlags() ([]string, error) {
+	flags := []string{"stats", "--format", "json"}
+	if opt.All {
+		flags = append(flags, "--all")
+	}
+	if opt.NoStream {
+		flags = append(flags, "--no-stream")
+	}
+	if opt.NoTrunc {
+		flags = append(flags, "--no-trunc")
+	}
+	return flags, nil
+}
+
+// ComposeAttachOptions is the options for the compose attach command.
+// Usage: docker compose attach [OPTIONS] SERVICE
+// Service is required; set via WithService.
+type ComposeAttachOptions struct {
+	DetachKeys string
+	Index      *int
+	NoStdin    bool
+	SigProxy   *bool // nil = default (true)
+	Service    string
+	Stdin      io.Reader // optional; forwarded to the container after compose file is read
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeAttachOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose attach command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"attach", "--no-stdin", "--sig-proxy=false"}
+func (opt *ComposeAttachOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"attach"}
+	if opt.DetachKeys != "" {
+		flags = append(flags, "--detach-keys", opt.DetachKeys)
+	}
+	if opt.Index != nil {
+		opt.addErrorWhen(*opt.Index < 1, "--index", "index must be greater than 0")
+		flags = append(flags, "--index", strconv.Itoa(*opt.Index))
+	}
+	if opt.NoStdin {
+		opt.addErrorWhen(opt.Stdin != nil, "--no-stdin", "WithNoStdin and WithStdin cannot be used together")
+		flags = append(flags, "--no-stdin")
+	}
+	if opt.SigProxy != nil {
+		flags = append(flags, "--sig-proxy="+strconv.FormatBool(*opt.SigProxy))
+	}
+	opt.addErrorWhen(opt.Service == "", "SERVICE", "service is required, set via WithService")
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	return flags, nil
+}
+
+// ComposePushOptions is the options for the compose push command
+type ComposePushOptions struct {
+	IgnorePushFailures bool
+	IncludeDeps        bool
+	Quiet              bool
+
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+// GenerateFlags generates the flags for the compose push command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"push", "--ignore-push-failures", "--quiet"}
+func (opt *ComposePushOptions) GenerateFlags() ([]string, error) {
+	flags := []string{"push"}
+	if opt.IgnorePushFailures {
+		flags = append(flags, "--ignore-push-failures")
+	}
+	if opt.IncludeDeps {
+		flags = append(flags, "--include-deps")
+	}
+	if opt.Quiet {
+		flags = append(flags, "--quiet")
+	}
+	return flags, nil
+}
+
+// ComposeScaleOptions is the options for the compose scale command.
+// Usage: docker compose scale [SERVICE=REPLICAS...]
+// At least one service is required; set via WithService.
+type ComposeScaleOptions struct {
+	NoDeps bool
+	Scale  []ComposeUpScale
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposeScaleOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose scale command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"scale", "--no-deps", "web=3", "worker=2"}
+func (opt *ComposeScaleOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"scale"}
+	if opt.NoDeps {
+		flags = append(flags, "--no-deps")
+	}
+	opt.addErrorWhen(len(opt.Scale) == 0, "SERVICE=REPLICAS", "at least one service is required, set via WithService")
+	for _, scale := range opt.Scale {
+		opt.addErrorWhen(scale.Service == "" || scale.Num < 0, "SERVICE=REPLICAS", "Service name required and scale must be non-negative")
+		flags = append(flags, fmt.Sprintf("%s=%d", scale.Service, scale.Num))
+	}
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	return flags, nil
+}
+
+// ComposePublishOptions is the options for the compose publish command.
+// Usage: docker compose publish [OPTIONS] REPOSITORY[:TAG]
+// Repository is required; set via WithRepository.
+type ComposePublishOptions struct {
+	OCIVersion          string // "1.0" or "1.1"
+	ResolveImageDigests bool
+	WithEnv             bool
+	Yes                 bool
+	Repository          string
+
+	Writer   io.Writer
+	Profiles []string
+	Errs     []error
+}
+
+// addErrorWhen adds an error to the error slice if the condition is true
+func (opt *ComposePublishOptions) addErrorWhen(cond bool, flag string, msg string) {
+	if cond {
+		opt.Errs = append(opt.Errs, NewComposeFlagError(flag, msg))
+	}
+}
+
+// GenerateFlags generates the flags for the compose publish command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"publish", "--resolve-image-digests", "registry.example.com/stack:1.0"}
+func (opt *ComposePublishOptions) GenerateFlags() ([]string, error) {
+	opt.Errs = []error{}
+	flags := []string{"publish"}
+	if opt.OCIVersion != "" {
+		opt.addErrorWhen(opt.OCIVersion != "1.0" && opt.OCIVersion != "1.1", "--oci-version", "oci version must be 1.0 or 1.1")
+		flags = append(flags, "--oci-version", opt.OCIVersion)
+	}
+	if opt.ResolveImageDigests {
+		flags = append(flags, "--resolve-image-digests")
+	}
+	if opt.WithEnv {
+		flags = append(flags, "--with-env")
+	}
+	if opt.Yes {
+		flags = append(flags, "--yes")
+	}
+	opt.addErrorWhen(opt.Repository == "", "REPOSITORY", "repository is required, set via WithRepository")
+	if len(opt.Errs) > 0 {
+		return nil, errors.Join(opt.Errs...)
+	}
+	flags = append(flags, opt.Repository)
+	return flags, nil
+}
+
+// ComposeWatchOptions is the options for the compose watch command
+type ComposeWatchOptions struct {
+	NoUp  bool
+	Prune bool
+	Quiet bool
+
+	Writer       io.Writer
+	Profiles     []string
+	ServiceNames []string
+}
+
+// GenerateFlags generates the flags for the compose watch command
+//
+// It will return a slice of flags to append to the command Eg.
+//
+//	[]string{"watch", "--no-up", "--prune"}
+func (opt *ComposeWatchOptions) GenerateFlags() ([]string, error) {
+	flags := []string{"watch"}
+	if opt.NoUp {
+		flags = append(flags, "--no-up")
+	}
+	if opt.Prune {
+		flags = append(flags, "--prune")
+	}
+	if opt.Quiet {
+		flags = append(flags, "--quiet")
+	}
+	return flags, nil
+}
@@ -0,0 +1,130 @@
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComposeRunOptionsGenerateFlags(t *testing.T) {
+	entrypoint := "/bin/sh"
+	opt := &ComposeRunOptions{
+		Remove:     true,
+		Entrypoint: &entrypoint,
+		Env:        []string{"A=1"},
+		Publish:    []string{"8080:80"},
+	}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"run", "--entrypoint", "/bin/sh", "--env", "A=1", "--publish", "8080:80", "--rm"}, flags)
+
+	opt.ServicePorts = true
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposeCreateOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposeCreateOptions{Build: true, RemoveOrphans: true, Scale: []ComposeUpScale{{Service: "web", Num: 2}}}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"create", "--build", "--remove-orphans", "--scale", "web=2"}, flags)
+
+	opt = &ComposeCreateOptions{Build: true, NoBuild: true}
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+
+	opt = &ComposeCreateOptions{ForceRecreate: true, NoRecreate: true}
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposeRmOptionsGenerateFlags(t *testing.T) {
+	// stdin carries the compose file, the confirmation prompt is always skipped
+	flags, err := (&ComposeRmOptions{}).GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rm", "--force"}, flags)
+
+	flags, err = (&ComposeRmOptions{Force: true, Stop: true, Volumes: true}).GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rm", "--force", "--stop", "--volumes"}, flags)
+}
+
+func TestComposeWaitOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposeWaitOptions{DownProject: true}
+	_, err := opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+
+	opt.ServiceNames = []string{"job"}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"wait", "--down-project"}, flags)
+}
+
+func TestComposeCpOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposeCpOptions{Archive: true, Source: "web:/etc/nginx/nginx.conf", Destination: "./nginx.conf"}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cp", "--archive", "web:/etc/nginx/nginx.conf", "./nginx.conf"}, flags)
+
+	_, err = (&ComposeCpOptions{}).GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposePortOptionsGenerateFlags(t *testing.T) {
+	index := 2
+	opt := &ComposePortOptions{Index: &index, Protocol: "udp", Service: "dns", PrivatePort: 53}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"port", "--index", "2", "--protocol", "udp", "dns", "53"}, flags)
+
+	opt = &ComposePortOptions{Protocol: "sctp", Service: "dns"}
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposeConfigOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposeConfigOptions{Format: "json", Hash: []string{}, NoInterpolate: true}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"config", "--format", "json", "--hash", "*", "--no-interpolate"}, flags)
+
+	opt = &ComposeConfigOptions{Services: true, Volumes: true}
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposeAttachOptionsGenerateFlags(t *testing.T) {
+	sigProxy := false
+	opt := &ComposeAttachOptions{Service: "web", NoStdin: true, SigProxy: &sigProxy, DetachKeys: "ctrl-x"}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"attach", "--detach-keys", "ctrl-x", "--no-stdin", "--sig-proxy=false"}, flags)
+
+	opt = &ComposeAttachOptions{Service: "web", NoStdin: true, Stdin: os.Stdin}
+	_, err = opt.GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposeScaleOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposeScaleOptions{NoDeps: true, Scale: []ComposeUpScale{{Service: "web", Num: 3}, {Service: "worker", Num: 0}}}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"scale", "--no-deps", "web=3", "worker=0"}, flags)
+
+	_, err = (&ComposeScaleOptions{}).GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+
+	_, err = (&ComposeScaleOptions{Scale: []ComposeUpScale{{Service: "web", Num: -1}}}).GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
+
+func TestComposePublishOptionsGenerateFlags(t *testing.T) {
+	opt := &ComposePublishOptions{OCIVersion: "1.1", Yes: true, Repository: "registry.example.com/stack:1.0"}
+	flags, err := opt.GenerateFlags()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"publish", "--oci-version", "1.1", "--yes", "registry.example.com/stack:1.0"}, flags)
+
+	_, err = (&ComposePublishOptions{}).GenerateFlags()
+	assert.True(t, IsComposeFlagError(err))
+}
@@ -10,21 +10,94 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/aptd3v/go-contain/pkg/create"
 	"github.com/compose-spec/compose-go/v2/types"
 )
 
 type compose struct {
-	project *create.Project
+	project       *create.Project
+	binary        string
+	base          []string
+	runner        CommandRunner
+	global        globalOptions
+	gracePeriod   time.Duration
+	contextErrors bool
+	dryRun        DryRunFunc
+	dryRunCLI     bool
+	version       *Version
+	versionErr    error
+	versionMu     sync.Mutex
+	errs          []error
 }
 
-func NewCompose(project *create.Project) *compose {
-	return &compose{
-		project: project,
+// SetComposeOption is a function that configures how a compose instance runs its commands
+type SetComposeOption func(*compose) error
+
+// Compose is the set of commands of a compose instance as returned by NewCompose.
+// Declare dependencies on Compose instead of the concrete type to decorate it or to swap it
+// for a fake in tests, see the composetest package.
+type Compose interface {
+	Up(ctx context.Context, setters ...SetComposeUpOption) error
+	Down(ctx context.Context, setters ...SetComposeDownOption) error
+	Logs(ctx context.Context, setters ...SetComposeLogsOption) error
+	LogStream(ctx context.Context, setters ...SetComposeLogsOption) (<-chan LogLine, <-chan error, error)
+	Kill(ctx context.Context, setters ...SetComposeKillOption) error
+	Ps(ctx context.Context, setters ...SetComposePsOption) error
+	PsList(ctx context.Context, setters ...SetComposePsOption) ([]ServiceContainer, error)
+	Start(ctx context.Context, setters ...SetComposeStartOption) error
+	Stop(ctx context.Context, setters ...SetComposeStopOption) error
+	Restart(ctx context.Context, setters ...SetComposeRestartOption) error
+	Build(ctx context.Context, setters ...SetComposeBuildOption) error
+	Pull(ctx context.Context, setters ...SetComposePullOption) error
+	Exec(ctx context.Context, setters ...SetComposeExecOption) error
+	Run(ctx context.Context, setters ...SetComposeRunOption) error
+	Create(ctx context.Context, setters ...SetComposeCreateOption) error
+	Rm(ctx context.Context, setters ...SetComposeRmOption) error
+	Pause(ctx context.Context, setters ...SetComposePauseOption) error
+	Unpause(ctx context.Context, setters ...SetComposeUnpauseOption) error
+	Wait(ctx context.Context, setters ...SetComposeWaitOption) error
+	Cp(ctx context.Context, setters ...SetComposeCpOption) error
+	Attach(ctx context.Context, setters ...SetComposeAttachOption) error
+	Push(ctx context.Context, setters ...SetComposePushOption) error
+	Scale(ctx context.Context, setters ...SetComposeScaleOption) error
+	Publish(ctx context.Context, setters ...SetComposePublishOption) error
+	Events(ctx context.Context, service string, profiles ...string) (<-chan Events, <-chan error, error)
+	Config(ctx context.Context, setters ...SetComposeConfigOption) (*ConfigResult, error)
+	Ls(ctx context.Context, setters ...SetComposeLsOption) ([]ProjectSummary, error)
+	Images(ctx context.Context, setters ...SetComposeImagesOption) ([]ServiceImage, error)
+	Port(ctx context.Context, setters ...SetComposePortOption) (*PortBinding, error)
+	Stats(ctx context.Context, setters ...SetComposeStatsOption) (<-chan StatsSample, <-chan error, error)
+	Top(ctx context.Context, setters ...SetComposeTopOption) ([]ContainerTop, error)
+	Watch(ctx context.Context, setters ...SetComposeWatchOption) (<-chan WatchEvent, <-chan error, error)
+	Version(ctx context.Context) (Version, error)
+}
+
+var _ Compose = (*compose)(nil)
+
+// NewCompose creates a new compose instance for the given project.
+// By default commands are executed as "docker compose" via os/exec,
+// use WithBinary or WithRunner to change this.
+func NewCompose(project *create.Project, setters ...SetComposeOption) *compose {
+	c := &compose{
+		project:     project,
+		binary:      "docker",
+		base:        []string{"compose"},
+		runner:      &execRunner{},
+		gracePeriod: DefaultGracePeriod,
+	}
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(c); err != nil {
+			c.errs = append(c.errs, err)
+		}
 	}
+	return c
 }
 
 // SetComposeUpOption is a function that sets a ComposeUpOptions
@@ -60,6 +133,60 @@ type SetComposePullOption func(*ComposePullOptions) error
 // SetComposeExecOption is a function that sets a ComposeExecOptions
 type SetComposeExecOption func(*ComposeExecOptions) error
 
+// SetComposeRunOption is a function that sets a ComposeRunOptions
+type SetComposeRunOption func(*ComposeRunOptions) error
+
+// SetComposeCreateOption is a function that sets a ComposeCreateOptions
+type SetComposeCreateOption func(*ComposeCreateOptions) error
+
+// SetComposeRmOption is a function that sets a ComposeRmOptions
+type SetComposeRmOption func(*ComposeRmOptions) error
+
+// SetComposePauseOption is a function that sets a ComposePauseOptions
+type SetComposePauseOption func(*ComposePauseOptions) error
+
+// SetComposeUnpauseOption is a function that sets a ComposeUnpauseOptions
+type SetComposeUnpauseOption func(*ComposeUnpauseOptions) error
+
+// SetComposeWaitOption is a function that sets a ComposeWaitOptions
+type SetComposeWaitOption func(*ComposeWaitOptions) error
+
+// SetComposeCpOption is a function that sets a ComposeCpOptions
+type SetComposeCpOption func(*ComposeCpOptions) error
+
+// SetComposeTopOption is a function that sets a ComposeTopOptions
+type SetComposeTopOption func(*ComposeTopOptions) error
+
+// SetComposePortOption is a function that sets a ComposePortOptions
+type SetComposePortOption func(*ComposePortOptions) error
+
+// SetComposeConfigOption is a function that sets a ComposeConfigOptions
+type SetComposeConfigOption func(*ComposeConfigOptions) error
+
+// SetComposeLsOption is a function that sets a ComposeLsOptions
+type SetComposeLsOption func(*ComposeLsOptions) error
+
+// SetComposeImagesOption is a function that sets a ComposeImagesOptions
+type SetComposeImagesOption func(*ComposeImagesOptions) error
+
+// SetComposeStatsOption is a function that sets a ComposeStatsOptions
+type SetComposeStatsOption func(*ComposeStatsOptions) error
+
+// SetComposeAttachOption is a function that sets a ComposeAttachOptions
+type SetComposeAttachOption func(*ComposeAttachOptions) error
+
+// SetComposePushOption is a function that sets a ComposePushOptions
+type SetComposePushOption func(*ComposePushOptions) error
+
+// SetComposeScaleOption is a function that sets a ComposeScaleOptions
+type SetComposeScaleOption func(*ComposeScaleOptions) error
+
+// SetComposePublishOption is a function that sets a ComposePublishOptions
+type SetComposePublishOption func(*ComposePublishOptions) error
+
+// SetComposeWatchOption is a function that sets a ComposeWatchOptions
+type SetComposeWatchOption func(*ComposeWatchOptions) error
+
 // Events runs the docker compose events command.
 // Pass an empty service string to receive events for all services.
 // When the project uses profiles, pass the same profiles used for up/down (e.g. Events(ctx, "", "minimal", "full")).
@@ -81,7 +208,7 @@ func (c *compose) Events(ctx context.Context, service string, profiles ...string
 		defer close(eventsCh)
 		defer close(errCh)
 
-		if err := handleContextCancellation(ctx, cmd.Run()); err != nil {
+		if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
 			errCh <- NewComposeEventsError(err)
 		}
 	}()
@@ -102,7 +229,7 @@ func (c *compose) Kill(ctx context.Context, setters ...SetComposeKillOption) err
 			return NewComposeKillError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeKillError(err)
 	}
@@ -110,7 +237,10 @@ func (c *compose) Kill(ctx context.Context, setters ...SetComposeKillOption) err
 	if err != nil {
 		return NewComposeKillError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeKillError(err)
+	}
+	return nil
 }
 
 func (c *compose) Up(ctx context.Context, setters ...SetComposeUpOption) error {
@@ -124,7 +254,7 @@ func (c *compose) Up(ctx context.Context, setters ...SetComposeUpOption) error {
 			return NewComposeUpError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeUpError(err)
 	}
@@ -132,7 +262,14 @@ func (c *compose) Up(ctx context.Context, setters ...SetComposeUpOption) error {
 	if err != nil {
 		return NewComposeUpError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if opt.Progress != nil {
+		flush := reportProgress(cmd, opt.Writer, opt.Progress)
+		defer flush()
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeUpError(err)
+	}
+	return nil
 }
 
 func (c *compose) Down(ctx context.Context, setters ...SetComposeDownOption) error {
@@ -145,7 +282,7 @@ func (c *compose) Down(ctx context.Context, setters ...SetComposeDownOption) err
 			return NewComposeDownError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeDownError(err)
 	}
@@ -154,7 +291,10 @@ func (c *compose) Down(ctx context.Context, setters ...SetComposeDownOption) err
 	if err != nil {
 		return NewComposeDownError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeDownError(err)
+	}
+	return nil
 }
 
 // Logs is a function that runs the docker compose logs command
@@ -170,7 +310,7 @@ func (c *compose) Logs(ctx context.Context, setters ...SetComposeLogsOption) err
 			return NewComposeLogsError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeLogsError(err)
 	}
@@ -180,7 +320,10 @@ func (c *compose) Logs(ctx context.Context, setters ...SetComposeLogsOption) err
 		return NewComposeLogsError(err)
 	}
 
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeLogsError(err)
+	}
+	return nil
 }
 
 // Ps runs the docker compose ps command.
@@ -191,7 +334,7 @@ func (c *compose) Ps(ctx context.Context, setters ...SetComposePsOption) error {
 			return NewComposePsError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposePsError(err)
 	}
@@ -200,7 +343,10 @@ func (c *compose) Ps(ctx context.Context, setters ...SetComposePsOption) error {
 	if err != nil {
 		return NewComposePsError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposePsError(err)
+	}
+	return nil
 }
 
 // Start runs the docker compose start command.
@@ -211,7 +357,7 @@ func (c *compose) Start(ctx context.Context, setters ...SetComposeStartOption) e
 			return NewComposeStartError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeStartError(err)
 	}
@@ -220,7 +366,10 @@ func (c *compose) Start(ctx context.Context, setters ...SetComposeStartOption) e
 	if err != nil {
 		return NewComposeStartError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeStartError(err)
+	}
+	return nil
 }
 
 // Stop runs the docker compose stop command.
@@ -231,7 +380,7 @@ func (c *compose) Stop(ctx context.Context, setters ...SetComposeStopOption) err
 			return NewComposeStopError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeStopError(err)
 	}
@@ -240,7 +389,10 @@ func (c *compose) Stop(ctx context.Context, setters ...SetComposeStopOption) err
 	if err != nil {
 		return NewComposeStopError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeStopError(err)
+	}
+	return nil
 }
 
 // Restart runs the docker compose restart command.
@@ -251,7 +403,7 @@ func (c *compose) Restart(ctx context.Context, setters ...SetComposeRestartOptio
 			return NewComposeRestartError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeRestartError(err)
 	}
@@ -260,7 +412,10 @@ func (c *compose) Restart(ctx context.Context, setters ...SetComposeRestartOptio
 	if err != nil {
 		return NewComposeRestartError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeRestartError(err)
+	}
+	return nil
 }
 
 // Build runs the docker compose build command.
@@ -271,7 +426,7 @@ func (c *compose) Build(ctx context.Context, setters ...SetComposeBuildOption) e
 			return NewComposeBuildError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeBuildError(err)
 	}
@@ -281,7 +436,14 @@ func (c *compose) Build(ctx context.Context, setters ...SetComposeBuildOption) e
 	if err != nil {
 		return NewComposeBuildError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if opt.Progress != nil {
+		flush := reportProgress(cmd, opt.Writer, opt.Progress)
+		defer flush()
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeBuildError(err)
+	}
+	return nil
 }
 
 // Pull runs the docker compose pull command.
@@ -292,7 +454,7 @@ func (c *compose) Pull(ctx context.Context, setters ...SetComposePullOption) err
 			return NewComposePullError(err)
 		}
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposePullError(err)
 	}
@@ -302,7 +464,14 @@ func (c *compose) Pull(ctx context.Context, setters ...SetComposePullOption) err
 	if err != nil {
 		return NewComposePullError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if opt.Progress != nil {
+		flush := reportProgress(cmd, opt.Writer, opt.Progress)
+		defer flush()
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposePullError(err)
+	}
+	return nil
 }
 
 // Exec runs the docker compose exec command.
@@ -321,7 +490,7 @@ func (c *compose) Exec(ctx context.Context, setters ...SetComposeExecOption) err
 	if len(opt.Command) == 0 {
 		return NewComposeExecError(fmt.Errorf("command is required"))
 	}
-	flags, err := opt.GenerateFlags()
+	flags, err := c.generateFlags(ctx, opt)
 	if err != nil {
 		return NewComposeExecError(err)
 	}
@@ -331,15 +500,285 @@ func (c *compose) Exec(ctx context.Context, setters ...SetComposeExecOption) err
 	if err != nil {
 		return NewComposeExecError(err)
 	}
-	return handleContextCancellation(ctx, cmd.Run())
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeExecError(err)
+	}
+	return nil
 }
 
-func (c *compose) command(ctx context.Context, writer io.Writer, args []string, profiles []string, stdin io.Reader) (*exec.Cmd, error) {
+// Run runs the docker compose run command.
+// Service must be set (e.g. WithService("migrate")); Command is optional and overrides the service command.
+// When the one-off container exits with a non-zero code, the returned *ComposeRunError carries it in ExitCode.
+func (c *compose) Run(ctx context.Context, setters ...SetComposeRunOption) error {
+	opt := &ComposeRunOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeRunError(err)
+		}
+	}
+	if opt.Service == "" {
+		return NewComposeRunError(fmt.Errorf("service is required"))
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeRunError(err)
+	}
+	flags = append(flags, opt.Service)
+	flags = append(flags, opt.Command...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, opt.Stdin)
+	if err != nil {
+		return NewComposeRunError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeRunError(err)
+	}
+	return nil
+}
+
+// Create runs the docker compose create command.
+func (c *compose) Create(ctx context.Context, setters ...SetComposeCreateOption) error {
+	opt := &ComposeCreateOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeCreateError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeCreateError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeCreateError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeCreateError(err)
+	}
+	return nil
+}
+
+// Rm runs the docker compose rm command.
+// The command always runs with --force, stdin carries the compose file and cannot answer the confirmation prompt.
+func (c *compose) Rm(ctx context.Context, setters ...SetComposeRmOption) error {
+	opt := &ComposeRmOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeRmError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeRmError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeRmError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeRmError(err)
+	}
+	return nil
+}
+
+// Pause runs the docker compose pause command.
+func (c *compose) Pause(ctx context.Context, setters ...SetComposePauseOption) error {
+	opt := &ComposePauseOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposePauseError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposePauseError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposePauseError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposePauseError(err)
+	}
+	return nil
+}
+
+// Unpause runs the docker compose unpause command.
+func (c *compose) Unpause(ctx context.Context, setters ...SetComposeUnpauseOption) error {
+	opt := &ComposeUnpauseOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeUnpauseError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeUnpauseError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeUnpauseError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeUnpauseError(err)
+	}
+	return nil
+}
+
+// Wait runs the docker compose wait command.
+// It blocks until the containers of the given services stop.
+// When the first container exits with a non-zero code, the returned *ComposeWaitError carries it in ExitCode.
+func (c *compose) Wait(ctx context.Context, setters ...SetComposeWaitOption) error {
+	opt := &ComposeWaitOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeWaitError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeWaitError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeWaitError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeWaitError(err)
+	}
+	return nil
+}
+
+// Cp runs the docker compose cp command.
+// Use cp.WithFrom to copy out of a service container or cp.WithTo to copy into one.
+func (c *compose) Cp(ctx context.Context, setters ...SetComposeCpOption) error {
+	opt := &ComposeCpOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeCpError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeCpError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeCpError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeCpError(err)
+	}
+	return nil
+}
+
+// Attach runs the docker compose attach command.
+// Service must be set (e.g. WithService("web")).
+// Use WithStdin(reader) to forward stdin to the service's main process (e.g. os.Stdin for interactive).
+func (c *compose) Attach(ctx context.Context, setters ...SetComposeAttachOption) error {
+	opt := &ComposeAttachOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeAttachError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeAttachError(err)
+	}
+	flags = append(flags, opt.Service)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, opt.Stdin)
+	if err != nil {
+		return NewComposeAttachError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeAttachError(err)
+	}
+	return nil
+}
+
+// Push runs the docker compose push command.
+func (c *compose) Push(ctx context.Context, setters ...SetComposePushOption) error {
+	opt := &ComposePushOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposePushError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposePushError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposePushError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposePushError(err)
+	}
+	return nil
+}
+
+// Scale runs the docker compose scale command.
+// It scales the given services without recreating the rest of the project.
+func (c *compose) Scale(ctx context.Context, setters ...SetComposeScaleOption) error {
+	opt := &ComposeScaleOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposeScaleError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposeScaleError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposeScaleError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposeScaleError(err)
+	}
+	return nil
+}
+
+// Publish runs the docker compose publish command.
+// It publishes the generated compose model as an OCI artifact to the given repository.
+func (c *compose) Publish(ctx context.Context, setters ...SetComposePublishOption) error {
+	opt := &ComposePublishOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return NewComposePublishError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return NewComposePublishError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return NewComposePublishError(err)
+	}
+	if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+		return NewComposePublishError(err)
+	}
+	return nil
+}
+
+func (c *compose) command(ctx context.Context, writer io.Writer, args []string, profiles []string, stdin io.Reader) (*Command, error) {
+	if len(c.errs) > 0 {
+		return nil, NewComposeError(errors.Join(c.errs...))
+	}
 	file, err := c.project.Marshal()
 	if err != nil {
 		return nil, NewComposeError(err)
 	}
-	base := []string{"compose"}
+	base := append([]string{}, c.base...)
 
 	// if profiles args are passed, we need to check if any services have profiles
 	// if they do not, we need to return an error
@@ -385,10 +824,19 @@ func (c *compose) command(ctx context.Context, writer io.Writer, args []string,
 		}
 	}
 
+	base = append(base, c.global.projectFlags()...)
+	base = append(base, c.global.outputFlags()...)
+	base = append(base, c.dryRunFlags()...)
 	// for file passed via stdin, we need to add the -f flag
 	base = append(base, "-f", "-")
-	cmd := exec.CommandContext(ctx, "docker", base...)
-	cmd.Args = append(cmd.Args, args...)
+	cmd := &Command{
+		Name:        c.binary,
+		Args:        append(base, args...),
+		Env:         c.global.environ(),
+		GracePeriod: c.gracePeriod,
+		subcommand:  args[0],
+		file:        file,
+	}
 	fileReader := strings.NewReader(string(file))
 	if stdin != nil {
 		cmd.Stdin = io.MultiReader(fileReader, stdin)
@@ -400,10 +848,51 @@ func (c *compose) command(ctx context.Context, writer io.Writer, args []string,
 	return cmd, nil
 }
 
+// globalCommand creates a docker compose command that does not operate on the project (e.g. ls)
+func (c *compose) globalCommand(writer io.Writer, args []string) (*Command, error) {
+	if len(c.errs) > 0 {
+		return nil, NewComposeError(errors.Join(c.errs...))
+	}
+	base := append([]string{}, c.base...)
+	base = append(base, c.global.outputFlags()...)
+	base = append(base, c.dryRunFlags()...)
+	cmd := &Command{
+		Name:        c.binary,
+		Args:        append(base, args...),
+		Env:         c.global.environ(),
+		Stdout:      writer,
+		Stderr:      writer,
+		GracePeriod: c.gracePeriod,
+		subcommand:  args[0],
+	}
+	return cmd, nil
+}
+
+// handleQueryCancellation returns the error of a command whose output is parsed, e.g. ps or ls.
+// Unlike handleContextCancellation a command stopped by the context always fails, its output is incomplete.
+// The error is a ComposeCanceledError or ComposeDeadlineExceededError whether or not WithContextErrors is set.
+func handleQueryCancellation(ctx context.Context, cmd *Command, err error) error {
+	if err == nil || IsComposeCanceledError(err) || IsComposeDeadlineExceededError(err) {
+		return err
+	}
+	switch {
+	case ctx.Err() == context.Canceled, errors.Is(err, context.Canceled):
+		return NewComposeCanceledError(cmd.subcommand, err)
+	case ctx.Err() == context.DeadlineExceeded, errors.Is(err, context.DeadlineExceeded):
+		return NewComposeDeadlineExceededError(cmd.subcommand, err)
+	}
+	return err
+}
+
+// handleContextCancellation treats a command stopped by the context as success,
+// unless the compose instance was created WithContextErrors
 func handleContextCancellation(ctx context.Context, err error) error {
 	if err == nil {
 		return nil
 	}
+	if IsComposeCanceledError(err) || IsComposeDeadlineExceededError(err) {
+		return err
+	}
 	if errors.Is(err, context.Canceled) {
 		return nil
 	}
@@ -0,0 +1,472 @@
+// Package composetest provides an in-memory implementation of compose.Compose for tests.
+//
+// A Fake never runs docker compose, it records every call with the options its setters produced
+// and returns the results scripted with its With methods:
+//
+//	fake := composetest.NewFake().
+//		WithError("Up", errors.New("port is already allocated")).
+//		WithPsList(compose.ServiceContainer{Name: "demo-web-1", Service: "web", State: "running"})
+//
+//	err := deploy(ctx, fake) // deploy accepts a compose.Compose
+//	calls := fake.CallsTo("Up")
+package composetest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// Call is a recorded call of a Fake
+type Call struct {
+	// Method is the name of the called compose.Compose method, e.g. "Up"
+	Method string
+	// Options is a pointer to the options struct the setters of the call were applied to,
+	// e.g. *compose.ComposeUpOptions for Up, an *EventsArgs for Events and nil for Version
+	Options any
+}
+
+// EventsArgs are the arguments of a recorded Events call
+type EventsArgs struct {
+	Service  string
+	Profiles []string
+}
+
+// Fake is an in-memory compose.Compose that records calls and returns scripted results.
+// It is safe for concurrent use.
+type Fake struct {
+	mu        sync.Mutex
+	calls     []Call
+	errs      map[string]error
+	psList    []compose.ServiceContainer
+	events    []compose.Events
+	logLines  []compose.LogLine
+	stats     []compose.StatsSample
+	watch     []compose.WatchEvent
+	config    *compose.ConfigResult
+	ls        []compose.ProjectSummary
+	images    []compose.ServiceImage
+	port      *compose.PortBinding
+	top       []compose.ContainerTop
+	version   compose.Version
+	streamErr map[string]error
+}
+
+var _ compose.Compose = (*Fake)(nil)
+
+// NewFake creates a new Fake without scripted results, every command succeeds.
+func NewFake() *Fake {
+	return &Fake{
+		errs:      map[string]error{},
+		streamErr: map[string]error{},
+	}
+}
+
+// WithError makes the method return err, e.g. WithError("Up", err).
+// Streaming methods (Events, LogStream, Stats and Watch) return err instead of their channels,
+// see WithStreamError to fail while streaming.
+func (f *Fake) WithError(method string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.errs[method] = err
+	return f
+}
+
+// WithStreamError makes the streaming method send err on its error channel after the scripted items.
+func (f *Fake) WithStreamError(method string, err error) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.streamErr[method] = err
+	return f
+}
+
+// WithPsList sets the containers returned by PsList, they are filtered by ps.WithServiceNames.
+func (f *Fake) WithPsList(containers ...compose.ServiceContainer) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.psList = append(f.psList, containers...)
+	return f
+}
+
+// WithEvents sets the events streamed by Events, they are filtered by the service argument.
+func (f *Fake) WithEvents(events ...compose.Events) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, events...)
+	return f
+}
+
+// WithLogLines sets the lines streamed by LogStream, Logs writes their messages to its writer.
+func (f *Fake) WithLogLines(lines ...compose.LogLine) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.logLines = append(f.logLines, lines...)
+	return f
+}
+
+// WithStats sets the samples streamed by Stats.
+func (f *Fake) WithStats(samples ...compose.StatsSample) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats = append(f.stats, samples...)
+	return f
+}
+
+// WithWatchEvents sets the events streamed by Watch.
+func (f *Fake) WithWatchEvents(events ...compose.WatchEvent) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.watch = append(f.watch, events...)
+	return f
+}
+
+// WithConfig sets the result of Config (default: an empty result).
+func (f *Fake) WithConfig(result *compose.ConfigResult) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.config = result
+	return f
+}
+
+// WithLs sets the projects returned by Ls.
+func (f *Fake) WithLs(projects ...compose.ProjectSummary) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ls = append(f.ls, projects...)
+	return f
+}
+
+// WithImages sets the images returned by Images.
+func (f *Fake) WithImages(images ...compose.ServiceImage) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.images = append(f.images, images...)
+	return f
+}
+
+// WithPort sets the binding returned by Port (default: an empty binding).
+func (f *Fake) WithPort(binding compose.PortBinding) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.port = &binding
+	return f
+}
+
+// WithTop sets the process tables returned by Top.
+func (f *Fake) WithTop(tables ...compose.ContainerTop) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.top = append(f.top, tables...)
+	return f
+}
+
+// WithVersion sets the version returned by Version (default: 0.0.0).
+func (f *Fake) WithVersion(version compose.Version) *Fake {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.version = version
+	return f
+}
+
+// Calls returns the recorded calls in order.
+func (f *Fake) Calls() []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.calls)
+}
+
+// CallsTo returns the recorded calls of the method in order.
+func (f *Fake) CallsTo(method string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := []Call{}
+	for _, call := range f.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Called reports whether the method was called at least once.
+func (f *Fake) Called(method string) bool {
+	return len(f.CallsTo(method)) > 0
+}
+
+// Reset forgets the recorded calls, scripted results are kept.
+func (f *Fake) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = nil
+}
+
+// call applies the setters to opt, records the call and returns the setter error wrapped like
+// the compose instance wraps it, or the scripted error of the method
+func call[T any, S ~func(*T) error, E error](f *Fake, method string, opt *T, setters []S, wrap func(error) E) error {
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(opt); err != nil {
+			return wrap(err)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: method, Options: opt})
+	return f.errs[method]
+}
+
+// stream sends the items on a new channel until they are exhausted or the context is done,
+// then sends err, if any, and closes both channels
+func stream[T any](ctx context.Context, items []T, err error) (<-chan T, <-chan error) {
+	ch := make(chan T)
+	errCh := make(chan error, 1)
+	go func() {
+		defer close(ch)
+		defer close(errCh)
+		for _, item := range items {
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- item:
+			}
+		}
+		if err != nil {
+			errCh <- err
+		}
+	}()
+	return ch, errCh
+}
+
+func (f *Fake) Up(ctx context.Context, setters ...compose.SetComposeUpOption) error {
+	return call(f, "Up", &compose.ComposeUpOptions{}, setters, compose.NewComposeUpError)
+}
+
+func (f *Fake) Down(ctx context.Context, setters ...compose.SetComposeDownOption) error {
+	return call(f, "Down", &compose.ComposeDownOptions{}, setters, compose.NewComposeDownError)
+}
+
+func (f *Fake) Logs(ctx context.Context, setters ...compose.SetComposeLogsOption) error {
+	opt := &compose.ComposeLogsOptions{}
+	if err := call(f, "Logs", opt, setters, compose.NewComposeLogsError); err != nil {
+		return err
+	}
+	if opt.Writer == nil {
+		return nil
+	}
+	f.mu.Lock()
+	lines := slices.Clone(f.logLines)
+	f.mu.Unlock()
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(opt.Writer, line.Message); err != nil {
+			return compose.NewComposeLogsError(err)
+		}
+	}
+	return nil
+}
+
+func (f *Fake) LogStream(ctx context.Context, setters ...compose.SetComposeLogsOption) (<-chan compose.LogLine, <-chan error, error) {
+	if err := call(f, "LogStream", &compose.ComposeLogsOptions{}, setters, compose.NewComposeLogsError); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	lines, errCh := stream(ctx, slices.Clone(f.logLines), f.streamErr["LogStream"])
+	return lines, errCh, nil
+}
+
+func (f *Fake) Kill(ctx context.Context, setters ...compose.SetComposeKillOption) error {
+	return call(f, "Kill", &compose.ComposeKillOptions{}, setters, compose.NewComposeKillError)
+}
+
+func (f *Fake) Ps(ctx context.Context, setters ...compose.SetComposePsOption) error {
+	return call(f, "Ps", &compose.ComposePsOptions{}, setters, compose.NewComposePsError)
+}
+
+func (f *Fake) PsList(ctx context.Context, setters ...compose.SetComposePsOption) ([]compose.ServiceContainer, error) {
+	opt := &compose.ComposePsOptions{}
+	if err := call(f, "PsList", opt, setters, compose.NewComposePsError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	containers := []compose.ServiceContainer{}
+	for _, c := range f.psList {
+		if len(opt.ServiceNames) > 0 && !slices.Contains(opt.ServiceNames, c.Service) {
+			continue
+		}
+		containers = append(containers, c)
+	}
+	return containers, nil
+}
+
+func (f *Fake) Start(ctx context.Context, setters ...compose.SetComposeStartOption) error {
+	return call(f, "Start", &compose.ComposeStartOptions{}, setters, compose.NewComposeStartError)
+}
+
+func (f *Fake) Stop(ctx context.Context, setters ...compose.SetComposeStopOption) error {
+	return call(f, "Stop", &compose.ComposeStopOptions{}, setters, compose.NewComposeStopError)
+}
+
+func (f *Fake) Restart(ctx context.Context, setters ...compose.SetComposeRestartOption) error {
+	return call(f, "Restart", &compose.ComposeRestartOptions{}, setters, compose.NewComposeRestartError)
+}
+
+func (f *Fake) Build(ctx context.Context, setters ...compose.SetComposeBuildOption) error {
+	return call(f, "Build", &compose.ComposeBuildOptions{}, setters, compose.NewComposeBuildError)
+}
+
+func (f *Fake) Pull(ctx context.Context, setters ...compose.SetComposePullOption) error {
+	return call(f, "Pull", &compose.ComposePullOptions{}, setters, compose.NewComposePullError)
+}
+
+func (f *Fake) Exec(ctx context.Context, setters ...compose.SetComposeExecOption) error {
+	return call(f, "Exec", &compose.ComposeExecOptions{}, setters, compose.NewComposeExecError)
+}
+
+func (f *Fake) Run(ctx context.Context, setters ...compose.SetComposeRunOption) error {
+	return call(f, "Run", &compose.ComposeRunOptions{}, setters, compose.NewComposeRunError)
+}
+
+func (f *Fake) Create(ctx context.Context, setters ...compose.SetComposeCreateOption) error {
+	return call(f, "Create", &compose.ComposeCreateOptions{}, setters, compose.NewComposeCreateError)
+}
+
+func (f *Fake) Rm(ctx context.Context, setters ...compose.SetComposeRmOption) error {
+	return call(f, "Rm", &compose.ComposeRmOptions{}, setters, compose.NewComposeRmError)
+}
+
+func (f *Fake) Pause(ctx context.Context, setters ...compose.SetComposePauseOption) error {
+	return call(f, "Pause", &compose.ComposePauseOptions{}, setters, compose.NewComposePauseError)
+}
+
+func (f *Fake) Unpause(ctx context.Context, setters ...compose.SetComposeUnpauseOption) error {
+	return call(f, "Unpause", &compose.ComposeUnpauseOptions{}, setters, compose.NewComposeUnpauseError)
+}
+
+func (f *Fake) Wait(ctx context.Context, setters ...compose.SetComposeWaitOption) error {
+	return call(f, "Wait", &compose.ComposeWaitOptions{}, setters, compose.NewComposeWaitError)
+}
+
+func (f *Fake) Cp(ctx context.Context, setters ...compose.SetComposeCpOption) error {
+	return call(f, "Cp", &compose.ComposeCpOptions{}, setters, compose.NewComposeCpError)
+}
+
+func (f *Fake) Attach(ctx context.Context, setters ...compose.SetComposeAttachOption) error {
+	return call(f, "Attach", &compose.ComposeAttachOptions{}, setters, compose.NewComposeAttachError)
+}
+
+func (f *Fake) Push(ctx context.Context, setters ...compose.SetComposePushOption) error {
+	return call(f, "Push", &compose.ComposePushOptions{}, setters, compose.NewComposePushError)
+}
+
+func (f *Fake) Scale(ctx context.Context, setters ...compose.SetComposeScaleOption) error {
+	return call(f, "Scale", &compose.ComposeScaleOptions{}, setters, compose.NewComposeScaleError)
+}
+
+func (f *Fake) Publish(ctx context.Context, setters ...compose.SetComposePublishOption) error {
+	return call(f, "Publish", &compose.ComposePublishOptions{}, setters, compose.NewComposePublishError)
+}
+
+func (f *Fake) Events(ctx context.Context, service string, profiles ...string) (<-chan compose.Events, <-chan error, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: "Events", Options: &EventsArgs{Service: service, Profiles: profiles}})
+	if err := f.errs["Events"]; err != nil {
+		return nil, nil, err
+	}
+	events := []compose.Events{}
+	for _, event := range f.events {
+		if service == "" || event.Service == service {
+			events = append(events, event)
+		}
+	}
+	eventsCh, errCh := stream(ctx, events, f.streamErr["Events"])
+	return eventsCh, errCh, nil
+}
+
+func (f *Fake) Config(ctx context.Context, setters ...compose.SetComposeConfigOption) (*compose.ConfigResult, error) {
+	if err := call(f, "Config", &compose.ComposeConfigOptions{}, setters, compose.NewComposeConfigError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.config == nil {
+		return &compose.ConfigResult{}, nil
+	}
+	return f.config, nil
+}
+
+func (f *Fake) Ls(ctx context.Context, setters ...compose.SetComposeLsOption) ([]compose.ProjectSummary, error) {
+	if err := call(f, "Ls", &compose.ComposeLsOptions{}, setters, compose.NewComposeLsError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]compose.ProjectSummary{}, f.ls...), nil
+}
+
+func (f *Fake) Images(ctx context.Context, setters ...compose.SetComposeImagesOption) ([]compose.ServiceImage, error) {
+	if err := call(f, "Images", &compose.ComposeImagesOptions{}, setters, compose.NewComposeImagesError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]compose.ServiceImage{}, f.images...), nil
+}
+
+func (f *Fake) Port(ctx context.Context, setters ...compose.SetComposePortOption) (*compose.PortBinding, error) {
+	if err := call(f, "Port", &compose.ComposePortOptions{}, setters, compose.NewComposePortError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.port == nil {
+		return &compose.PortBinding{}, nil
+	}
+	binding := *f.port
+	return &binding, nil
+}
+
+func (f *Fake) Stats(ctx context.Context, setters ...compose.SetComposeStatsOption) (<-chan compose.StatsSample, <-chan error, error) {
+	if err := call(f, "Stats", &compose.ComposeStatsOptions{}, setters, compose.NewComposeStatsError); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	samples, errCh := stream(ctx, slices.Clone(f.stats), f.streamErr["Stats"])
+	return samples, errCh, nil
+}
+
+func (f *Fake) Top(ctx context.Context, setters ...compose.SetComposeTopOption) ([]compose.ContainerTop, error) {
+	if err := call(f, "Top", &compose.ComposeTopOptions{}, setters, compose.NewComposeTopError); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]compose.ContainerTop{}, f.top...), nil
+}
+
+func (f *Fake) Watch(ctx context.Context, setters ...compose.SetComposeWatchOption) (<-chan compose.WatchEvent, <-chan error, error) {
+	if err := call(f, "Watch", &compose.ComposeWatchOptions{}, setters, compose.NewComposeWatchError); err != nil {
+		return nil, nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events, errCh := stream(ctx, slices.Clone(f.watch), f.streamErr["Watch"])
+	return events, errCh, nil
+}
+
+func (f *Fake) Version(ctx context.Context) (compose.Version, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, Call{Method: "Version"})
+	if err := f.errs["Version"]; err != nil {
+		return compose.Version{}, err
+	}
+	return f.version, nil
+}
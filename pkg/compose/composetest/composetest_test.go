@@ -0,0 +1,110 @@
+package composetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/aptd3v/go-contain/pkg/compose/options/logs"
+	"github.com/aptd3v/go-contain/pkg/compose/options/ps"
+	"github.com/aptd3v/go-contain/pkg/compose/options/up"
+	"github.com/stretchr/testify/assert"
+)
+
+// deploy is what code under test typically looks like, it only knows the interface
+func deploy(ctx context.Context, c compose.Compose) error {
+	if err := c.Up(ctx, up.WithDetach(), up.WithRemoveOrphans()); err != nil {
+		return err
+	}
+	return c.Down(ctx)
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	fake := NewFake()
+	assert.NoError(t, deploy(context.Background(), fake))
+
+	calls := fake.Calls()
+	if !assert.Len(t, calls, 2) {
+		return
+	}
+	assert.Equal(t, "Up", calls[0].Method)
+	assert.Equal(t, "Down", calls[1].Method)
+	opt, ok := calls[0].Options.(*compose.ComposeUpOptions)
+	if assert.True(t, ok) {
+		assert.True(t, opt.Detach)
+		assert.True(t, opt.RemoveOrphans)
+	}
+	assert.True(t, fake.Called("Down"))
+	assert.False(t, fake.Called("Pull"))
+
+	fake.Reset()
+	assert.Empty(t, fake.Calls())
+}
+
+func TestFakeErrors(t *testing.T) {
+	scripted := compose.NewComposeUpError(errors.New("port is already allocated"))
+	fake := NewFake().WithError("Up", scripted)
+
+	err := deploy(context.Background(), fake)
+	assert.Equal(t, scripted, err)
+	assert.False(t, fake.Called("Down"))
+
+	// setter errors are wrapped like the compose instance wraps them
+	err = fake.Up(context.Background(), func(*compose.ComposeUpOptions) error {
+		return errors.New("invalid option")
+	})
+	assert.True(t, compose.IsComposeUpError(err))
+	assert.Len(t, fake.CallsTo("Up"), 1)
+}
+
+func TestFakePsList(t *testing.T) {
+	fake := NewFake().WithPsList(
+		compose.ServiceContainer{Name: "demo-web-1", Service: "web", State: "running"},
+		compose.ServiceContainer{Name: "demo-db-1", Service: "db", State: "running"},
+	)
+	containers, err := fake.PsList(context.Background(), ps.WithServiceNames("db"))
+	assert.NoError(t, err)
+	assert.Equal(t, []compose.ServiceContainer{{Name: "demo-db-1", Service: "db", State: "running"}}, containers)
+}
+
+func TestFakeEvents(t *testing.T) {
+	streamErr := errors.New("connection lost")
+	fake := NewFake().
+		WithEvents(
+			compose.Events{Service: "db", Type: compose.EventTypeContainer, Action: compose.EventActionStart},
+			compose.Events{Service: "web", Type: compose.EventTypeContainer, Action: compose.EventActionStart},
+		).
+		WithStreamError("Events", streamErr)
+
+	events, errCh, err := fake.Events(context.Background(), "web")
+	if !assert.NoError(t, err) {
+		return
+	}
+	event, err := compose.WaitFor(context.Background(), events, compose.ServiceStarted("web"))
+	assert.NoError(t, err)
+	assert.Equal(t, "web", event.Service)
+	assert.Equal(t, streamErr, <-errCh)
+	assert.Equal(t, &EventsArgs{Service: "web"}, fake.CallsTo("Events")[0].Options)
+}
+
+func TestFakeLogs(t *testing.T) {
+	fake := NewFake().WithLogLines(
+		compose.LogLine{Service: "web", Container: "web-1", Message: "ready"},
+	)
+	out := &bytes.Buffer{}
+	assert.NoError(t, fake.Logs(context.Background(), logs.WithWriter(out)))
+	assert.Equal(t, "ready\n", out.String())
+
+	lines, errCh, err := fake.LogStream(context.Background())
+	if !assert.NoError(t, err) {
+		return
+	}
+	got := []compose.LogLine{}
+	for line := range lines {
+		got = append(got, line)
+	}
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, "web", got[0].Service)
+}
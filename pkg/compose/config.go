@@ -0,0 +1,126 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// ConfigResult is the result of the docker compose config command
+type ConfigResult struct {
+	// Raw is the unmodified output of the command
+	Raw []byte
+	// Project is the canonical model as resolved by docker compose, nil when a listing option is set
+	Project *types.Project
+	// Services is set by config.WithServices
+	Services []string
+	// Volumes is set by config.WithVolumes
+	Volumes []string
+	// Images is set by config.WithImages
+	Images []string
+	// Hashes maps service names to their configuration hash, set by config.WithHash
+	Hashes map[string]string
+}
+
+// Config runs the docker compose config command against the generated project
+// and returns the fully resolved model as docker compose sees it.
+// The writer set with config.WithWriter only receives stderr output.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) Config(ctx context.Context, setters ...SetComposeConfigOption) (*ConfigResult, error) {
+	opt := &ComposeConfigOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposeConfigError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposeConfigError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, NewComposeConfigError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposeConfigError(err)
+	}
+	if c.skipsExecution() {
+		return &ConfigResult{}, nil
+	}
+	result, err := c.parseConfigOutput(ctx, opt, stdout.Bytes())
+	if err != nil {
+		return nil, NewComposeConfigError(err)
+	}
+	return result, nil
+}
+
+// parseConfigOutput parses the output of docker compose config according to the options it was run with
+func (c *compose) parseConfigOutput(ctx context.Context, opt *ComposeConfigOptions, data []byte) (*ConfigResult, error) {
+	result := &ConfigResult{Raw: data}
+	switch {
+	case opt.Services:
+		result.Services = splitLines(data)
+	case opt.Volumes:
+		result.Volumes = splitLines(data)
+	case opt.Images:
+		result.Images = splitLines(data)
+	case opt.Hash != nil:
+		result.Hashes = map[string]string{}
+		for _, line := range splitLines(data) {
+			fields := strings.Fields(line)
+			if len(fields) == 2 {
+				result.Hashes[fields[0]] = fields[1]
+			}
+		}
+	default:
+		name := c.project.Unwrap().Name
+		if c.global.projectName != "" {
+			name = c.global.projectName
+		}
+		project, err := loadConfigProject(ctx, name, c.global.projectDirectory, opt, data)
+		if err != nil {
+			return nil, err
+		}
+		result.Project = project
+	}
+	return result, nil
+}
+
+// loadConfigProject loads the yaml or json model printed by docker compose config,
+// relative paths are resolved against dir or the current working directory when empty
+func loadConfigProject(ctx context.Context, name, dir string, opt *ComposeConfigOptions, data []byte) (*types.Project, error) {
+	wd, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	details := types.ConfigDetails{
+		WorkingDir:  wd,
+		ConfigFiles: []types.ConfigFile{{Filename: "-", Content: data}},
+		Environment: types.NewMapping(os.Environ()),
+	}
+	return loader.LoadWithContext(ctx, details, func(o *loader.Options) {
+		o.SetProjectName(name, true)
+		o.SkipInterpolation = opt.NoInterpolate
+		o.Profiles = opt.Profiles
+	})
+}
+
+// splitLines returns the non-empty trimmed lines of data
+func splitLines(data []byte) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
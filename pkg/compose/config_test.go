@@ -0,0 +1,47 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfigOutputProject(t *testing.T) {
+	data, err := os.ReadFile("testdata/config.yaml")
+	if !assert.NoError(t, err) {
+		return
+	}
+	c := NewCompose(create.NewProject("demo"))
+	result, err := c.parseConfigOutput(context.Background(), &ComposeConfigOptions{}, data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, data, result.Raw)
+	if !assert.NotNil(t, result.Project) {
+		return
+	}
+	assert.Equal(t, "demo", result.Project.Name)
+	assert.ElementsMatch(t, []string{"web", "db"}, result.Project.ServiceNames())
+	web := result.Project.Services["web"]
+	assert.Equal(t, "nginx:alpine", web.Image)
+	assert.Equal(t, "9080", web.Ports[0].Published)
+	assert.Contains(t, web.DependsOn, "db")
+	assert.Equal(t, "demo_data", result.Project.Volumes["data"].Name)
+}
+
+func TestParseConfigOutputListings(t *testing.T) {
+	c := NewCompose(create.NewProject("demo"))
+	ctx := context.Background()
+
+	result, err := c.parseConfigOutput(ctx, &ComposeConfigOptions{Services: true}, []byte("web\ndb\n"))
+	assert.NoError(t, err)
+	assert.Nil(t, result.Project)
+	assert.Equal(t, []string{"web", "db"}, result.Services)
+
+	result, err = c.parseConfigOutput(ctx, &ComposeConfigOptions{Hash: []string{}}, []byte("web 3f1a\ndb 9c2b\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"web": "3f1a", "db": "9c2b"}, result.Hashes)
+}
@@ -0,0 +1,210 @@
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DryRunPlan describes a compose command that a compose instance in dry run mode would execute
+type DryRunPlan struct {
+	// Argv is the full command line including the binary name
+	Argv []string
+	// Compose is the rendered compose yaml passed on stdin, nil for commands that do not operate on the project (e.g. ls)
+	Compose []byte
+	// Env is the resolved environment of the process, including variables injected with WithEnv
+	Env []string
+	// Dir is the working directory of the process, empty for the current working directory
+	Dir string
+	// Actions are the planned actions reported by docker compose --dry-run, only set with WithCLIDryRun
+	Actions []DryRunAction
+}
+
+// DryRunAction is a single planned action reported by docker compose --dry-run,
+// e.g. "DRY-RUN MODE -  Container demo-web-1  Created"
+type DryRunAction struct {
+	// Resource is the kind of resource, e.g. "Container", "Network", "Volume" or "Image"
+	Resource string
+	// Name is the name of the resource, e.g. "demo-web-1"
+	Name string
+	// Action is the step compose would take, e.g. "Creating", "Created" or "Started"
+	Action string
+}
+
+// DryRunFunc receives the plan of every command of a compose instance in dry run mode
+type DryRunFunc func(DryRunPlan)
+
+// WithDryRun makes every command report its plan to fn instead of executing it.
+// Commands return without error, commands that parse the output of compose return empty results.
+//
+// Eg.
+//
+//	plans := []compose.DryRunPlan{}
+//	app := compose.NewCompose(project, compose.WithDryRun(func(p compose.DryRunPlan) {
+//		plans = append(plans, p)
+//	}))
+//	app.Up(ctx, up.WithDetach()) // nothing is executed
+func WithDryRun(fn DryRunFunc) SetComposeOption {
+	return func(c *compose) error {
+		if fn == nil {
+			return NewComposeError(fmt.Errorf("WithDryRun: dry run func is nil"))
+		}
+		c.dryRun = fn
+		c.dryRunCLI = false
+		return nil
+	}
+}
+
+// WithCLIDryRun executes every command with the --dry-run flag of docker compose,
+// which reports the planned actions without applying them, and sends the plan to fn
+// once the command exited. The planned actions are parsed into DryRunPlan.Actions.
+//
+// --dry-run		Execute command in dry run mode
+func WithCLIDryRun(fn DryRunFunc) SetComposeOption {
+	return func(c *compose) error {
+		if fn == nil {
+			return NewComposeError(fmt.Errorf("WithCLIDryRun: dry run func is nil"))
+		}
+		c.dryRun = fn
+		c.dryRunCLI = true
+		return nil
+	}
+}
+
+// skipsExecution reports whether commands are only planned and never executed
+func (c *compose) skipsExecution() bool {
+	return c.dryRun != nil && !c.dryRunCLI
+}
+
+// dryRunFlags returns the global flags of the dry run mode
+func (c *compose) dryRunFlags() []string {
+	if c.dryRun != nil && c.dryRunCLI {
+		return []string{"--dry-run"}
+	}
+	return nil
+}
+
+// plan returns the dry run plan of the command
+func (c *compose) plan(cmd *Command) DryRunPlan {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	return DryRunPlan{
+		Argv:    cmd.Argv(),
+		Compose: cmd.file,
+		Env:     slices.Clone(env),
+		Dir:     cmd.Dir,
+	}
+}
+
+var (
+	dryRunPrefix   = "DRY-RUN MODE -"
+	dryRunDuration = regexp.MustCompile(`^\d+(\.\d+)?s$`)
+)
+
+// parseDryRunLine parses a line of docker compose --dry-run output, ok is false for lines without a planned action
+func parseDryRunLine(line string) (DryRunAction, bool) {
+	line = strings.TrimSpace(ansiEscape.ReplaceAllString(line, ""))
+	if strings.HasPrefix(line, "{") {
+		return parseDryRunMessage(line)
+	}
+	_, rest, ok := strings.Cut(line, dryRunPrefix)
+	if !ok {
+		return DryRunAction{}, false
+	}
+	fields := strings.Fields(rest)
+	// the tty progress output ends with the elapsed time, e.g. "0.0s"
+	if n := len(fields); n > 0 && dryRunDuration.MatchString(fields[n-1]) {
+		fields = fields[:n-1]
+	}
+	if len(fields) < 2 {
+		return DryRunAction{}, false
+	}
+	return DryRunAction{
+		Resource: fields[0],
+		Name:     fields[1],
+		Action:   strings.Join(fields[2:], " "),
+	}, true
+}
+
+// parseDryRunMessage parses a line of --progress json output, see up.WithProgress
+func parseDryRunMessage(line string) (DryRunAction, bool) {
+	var msg progressMessage
+	if err := json.Unmarshal([]byte(line), &msg); err != nil || !msg.DryRun {
+		return DryRunAction{}, false
+	}
+	resource, name, ok := strings.Cut(msg.ID, " ")
+	if !ok {
+		return DryRunAction{}, false
+	}
+	return DryRunAction{Resource: resource, Name: strings.TrimSpace(name), Action: msg.Text}, true
+}
+
+// dryRunWriter collects the planned actions written to stdout and stderr of a command
+type dryRunWriter struct {
+	mu      sync.Mutex
+	actions []DryRunAction
+	buffers map[LogStream]*bytes.Buffer
+}
+
+func newDryRunWriter() *dryRunWriter {
+	return &dryRunWriter{buffers: map[LogStream]*bytes.Buffer{
+		LogStreamStdout: {},
+		LogStreamStderr: {},
+	}}
+}
+
+// stream returns a writer for one output stream, partial lines of both streams are kept apart
+func (w *dryRunWriter) stream(stream LogStream) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		buffer := w.buffers[stream]
+		buffer.Write(p)
+		for {
+			i := bytes.IndexAny(buffer.Bytes(), "\r\n")
+			if i < 0 {
+				return len(p), nil
+			}
+			w.add(string(buffer.Next(i + 1)))
+		}
+	})
+}
+
+// result returns the planned actions including the last incomplete lines
+func (w *dryRunWriter) result() []DryRunAction {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, stream := range []LogStream{LogStreamStdout, LogStreamStderr} {
+		w.add(w.buffers[stream].String())
+		w.buffers[stream].Reset()
+	}
+	return w.actions
+}
+
+func (w *dryRunWriter) add(line string) {
+	if action, ok := parseDryRunLine(line); ok {
+		w.actions = append(w.actions, action)
+	}
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+// teeWriter writes to w and extra, w may be nil
+func teeWriter(w io.Writer, extra io.Writer) io.Writer {
+	if w == nil {
+		return extra
+	}
+	return io.MultiWriter(w, extra)
+}
@@ -0,0 +1,97 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	runner := NewRecordingRunner()
+	plans := []DryRunPlan{}
+	c := NewCompose(testProject(),
+		WithRunner(runner),
+		WithEnv("TAG=1.2.3"),
+		WithDryRun(func(plan DryRunPlan) {
+			plans = append(plans, plan)
+		}),
+	)
+	ctx := context.Background()
+	err := c.Up(ctx, func(opt *ComposeUpOptions) error {
+		opt.Detach = true
+		return nil
+	})
+	assert.NoError(t, err)
+	binding, err := c.Port(ctx, func(opt *ComposePortOptions) error {
+		opt.Service = "web"
+		opt.PrivatePort = 80
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, &PortBinding{}, binding)
+	config, err := c.Config(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &ConfigResult{}, config)
+	_, err = c.Ls(ctx)
+	assert.NoError(t, err)
+
+	// nothing was executed
+	assert.Empty(t, runner.Commands())
+	if !assert.Len(t, plans, 4) {
+		return
+	}
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "up", "--detach"}, plans[0].Argv)
+	assert.Contains(t, string(plans[0].Compose), "image: nginx:alpine")
+	assert.Contains(t, plans[0].Env, "TAG=1.2.3")
+	assert.Nil(t, plans[0].Actions)
+	assert.Equal(t, []string{"docker", "compose", "ls", "--format", "json"}, plans[3].Argv)
+	assert.Nil(t, plans[3].Compose)
+}
+
+func TestCLIDryRun(t *testing.T) {
+	data, err := os.ReadFile("testdata/dryrun.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		// write in small chunks to exercise partial line buffering
+		for i := 0; i < len(data); i += 7 {
+			if _, err := cmd.Stderr.Write(data[i:min(i+7, len(data))]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	plans := []DryRunPlan{}
+	c := NewCompose(testProject(), WithRunner(runner), WithCLIDryRun(func(plan DryRunPlan) {
+		plans = append(plans, plan)
+	}))
+	assert.NoError(t, c.Up(context.Background()))
+
+	cmd, ok := runner.Last()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, []string{"docker", "compose", "--dry-run", "-f", "-", "up"}, cmd.Argv)
+	if !assert.Len(t, plans, 1) {
+		return
+	}
+	assert.Equal(t, cmd.Argv, plans[0].Argv)
+	assert.Equal(t, []DryRunAction{
+		{Resource: "Network", Name: "demo_default", Action: "Creating"},
+		{Resource: "Network", Name: "demo_default", Action: "Created"},
+		{Resource: "Container", Name: "demo-web-1", Action: "Creating"},
+		{Resource: "Container", Name: "demo-web-1", Action: "Created"},
+		{Resource: "Container", Name: "demo-web-1", Action: "Started"},
+		{Resource: "Volume", Name: "demo_data", Action: "Created"},
+		{Resource: "Image", Name: "nginx:alpine", Action: "Pulled"},
+	}, plans[0].Actions)
+}
+
+func TestWithDryRunNil(t *testing.T) {
+	c := NewCompose(testProject(), WithDryRun(nil))
+	assert.True(t, IsComposeUpError(c.Up(context.Background())))
+}
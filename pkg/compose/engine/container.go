@@ -0,0 +1,485 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/strslice"
+	"github.com/docker/go-connections/nat"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// configHash returns the hash of the service config stored in the LabelConfigHash label, computed like
+// ServiceHash of docker compose over the service loaded by loadProject.
+// Fields that do not affect the container are left out, so a changed hash means the container has to be recreated.
+func configHash(service types.ServiceConfig) (string, error) {
+	service.Build = nil
+	service.PullPolicy = ""
+	service.Scale = nil
+	if service.Deploy != nil {
+		deploy := *service.Deploy
+		replicas := 1
+		deploy.Replicas = &replicas
+		service.Deploy = &deploy
+	}
+	service.DependsOn = nil
+	service.Profiles = nil
+	data, err := json.Marshal(service)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// dependsOnLabel renders the depends_on config like docker compose, e.g. "db:service_healthy:true"
+func dependsOnLabel(service types.ServiceConfig) string {
+	deps := make([]string, 0, len(service.DependsOn))
+	for name, dep := range service.DependsOn {
+		deps = append(deps, fmt.Sprintf("%s:%s:%t", name, dep.Condition, dep.Restart))
+	}
+	sort.Strings(deps)
+	return strings.Join(deps, ",")
+}
+
+// newContainer converts replica number of the service into a container ready to be created
+func (m *model) newContainer(service types.ServiceConfig, number int, hash string) (*create.Container, error) {
+	env, err := serviceEnvironment(service, m.workingDir)
+	if err != nil {
+		return nil, err
+	}
+	labels := map[string]string{}
+	for k, v := range service.Labels {
+		labels[k] = v
+	}
+	labels[LabelProject] = m.project.Name
+	labels[LabelWorkingDir] = m.workingDir
+	labels[LabelService] = service.Name
+	labels[LabelContainerNumber] = strconv.Itoa(number)
+	labels[LabelConfigHash] = hash
+	labels[LabelOneoff] = "False"
+	labels[LabelDependsOn] = dependsOnLabel(service)
+
+	exposed, bindings, err := servicePorts(service)
+	if err != nil {
+		return nil, err
+	}
+	mounts := append(m.serviceMounts(service), m.fileMounts(service)...)
+	cfg := &container.Config{
+		Image:        service.Image,
+		Cmd:          strslice.StrSlice(service.Command),
+		Entrypoint:   strslice.StrSlice(service.Entrypoint),
+		Env:          env,
+		Labels:       labels,
+		Tty:          service.Tty,
+		OpenStdin:    service.StdinOpen,
+		WorkingDir:   service.WorkingDir,
+		User:         service.User,
+		Hostname:     service.Hostname,
+		Domainname:   service.DomainName,
+		StopSignal:   service.StopSignal,
+		ExposedPorts: exposed,
+		Healthcheck:  healthConfig(service.HealthCheck),
+	}
+	if service.StopGracePeriod != nil {
+		timeout := int(time.Duration(*service.StopGracePeriod).Seconds())
+		cfg.StopTimeout = &timeout
+	}
+
+	host := &container.HostConfig{
+		PortBindings:   bindings,
+		Mounts:         mounts,
+		RestartPolicy:  restartPolicy(service.Restart),
+		Privileged:     service.Privileged,
+		ReadonlyRootfs: service.ReadOnly,
+		CapAdd:         service.CapAdd,
+		CapDrop:        service.CapDrop,
+		DNS:            service.DNS,
+		DNSSearch:      service.DNSSearch,
+		DNSOptions:     service.DNSOpts,
+		ExtraHosts:     service.ExtraHosts.AsList(":"),
+		GroupAdd:       service.GroupAdd,
+		Init:           service.Init,
+		IpcMode:        container.IpcMode(service.Ipc),
+		PidMode:        container.PidMode(service.Pid),
+		UTSMode:        container.UTSMode(service.Uts),
+		UsernsMode:     container.UsernsMode(service.UserNSMode),
+		Runtime:        service.Runtime,
+		SecurityOpt:    service.SecurityOpt,
+		Sysctls:        service.Sysctls,
+		Tmpfs:          serviceTmpfs(service.Tmpfs),
+		VolumesFrom:    m.volumesFrom(service.VolumesFrom),
+		ShmSize:        int64(service.ShmSize),
+		Resources: container.Resources{
+			Memory:            int64(service.MemLimit),
+			MemoryReservation: int64(service.MemReservation),
+			MemorySwap:        int64(service.MemSwapLimit),
+			NanoCPUs:          int64(service.CPUS * 1e9),
+			CPUShares:         service.CPUShares,
+			CPUQuota:          service.CPUQuota,
+			CPUPeriod:         service.CPUPeriod,
+			CpusetCpus:        service.CPUSet,
+			Ulimits:           serviceUlimits(service.Ulimits),
+		},
+	}
+	if service.PidsLimit != 0 {
+		limit := service.PidsLimit
+		host.PidsLimit = &limit
+	}
+	if service.Deploy != nil {
+		deployResources(service.Deploy.Resources, &host.Resources)
+	}
+	if service.Logging != nil {
+		host.LogConfig = container.LogConfig{Type: service.Logging.Driver, Config: service.Logging.Options}
+	}
+
+	net := &network.NetworkingConfig{EndpointsConfig: map[string]*network.EndpointSettings{}}
+	switch {
+	case strings.HasPrefix(service.NetworkMode, "service:"):
+		// replica 1 of the referenced service is created first because it is a dependency
+		target := strings.TrimPrefix(service.NetworkMode, "service:")
+		ref, ok := m.project.Services[target]
+		if !ok {
+			return nil, fmt.Errorf("service %s uses network_mode of unknown service %s", service.Name, target)
+		}
+		host.NetworkMode = container.NetworkMode("container:" + m.containerName(ref, 1))
+	case service.NetworkMode != "":
+		host.NetworkMode = container.NetworkMode(service.NetworkMode)
+	default:
+		for i, key := range serviceNetworks(service) {
+			name := m.networkName(key)
+			if i == 0 {
+				host.NetworkMode = container.NetworkMode(name)
+			}
+			net.EndpointsConfig[name] = endpointSettings(service, service.Networks[key])
+		}
+	}
+
+	created := create.NewContainer(m.containerName(service, number))
+	created.Config.Container = cfg
+	created.Config.Host = host
+	created.Config.Network = net
+	created.Config.Platform = servicePlatform(service.Platform)
+	return created, nil
+}
+
+// volumesFrom resolves the services of volumes_from to the container of their first replica,
+// e.g. "db:ro" becomes "demo-db-1:ro" and "container:backup" becomes "backup"
+func (m *model) volumesFrom(volumesFrom []string) []string {
+	resolved := make([]string, 0, len(volumesFrom))
+	for _, from := range volumesFrom {
+		if name, ok := strings.CutPrefix(from, "container:"); ok {
+			resolved = append(resolved, name)
+			continue
+		}
+		name, mode, hasMode := strings.Cut(from, ":")
+		if service, ok := m.project.Services[name]; ok {
+			name = m.containerName(service, 1)
+		}
+		if hasMode {
+			name += ":" + mode
+		}
+		resolved = append(resolved, name)
+	}
+	return resolved
+}
+
+// serviceEnvironment resolves the env files and environment of the service like docker compose does,
+// relative env files are read from workingDir, variables without a value are taken from the current process
+// or dropped when not set
+func serviceEnvironment(service types.ServiceConfig, workingDir string) ([]string, error) {
+	files := []string{}
+	for _, file := range service.EnvFiles {
+		path := file.Path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(workingDir, path)
+		}
+		if _, err := os.Stat(path); err != nil {
+			if file.Required {
+				return nil, fmt.Errorf("service %s: env file %s not found: %w", service.Name, path, err)
+			}
+			continue
+		}
+		files = append(files, path)
+	}
+	vars, err := dotenv.GetEnvFromFile(map[string]string{}, files)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", service.Name, err)
+	}
+	for key, value := range service.Environment {
+		if value != nil {
+			vars[key] = *value
+			continue
+		}
+		if v, ok := os.LookupEnv(key); ok {
+			vars[key] = v
+		}
+	}
+	env := make([]string, 0, len(vars))
+	for key, value := range vars {
+		env = append(env, key+"="+value)
+	}
+	sort.Strings(env)
+	return env, nil
+}
+
+// servicePorts converts the ports and expose config of the service
+func servicePorts(service types.ServiceConfig) (nat.PortSet, nat.PortMap, error) {
+	exposed := nat.PortSet{}
+	bindings := nat.PortMap{}
+	for _, port := range service.Ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		spec := fmt.Sprintf("%d/%s", port.Target, protocol)
+		if port.Published != "" {
+			spec = fmt.Sprintf("%s:%s", port.Published, spec)
+			if port.HostIP != "" {
+				spec = fmt.Sprintf("%s:%s", port.HostIP, spec)
+			}
+		}
+		mappings, err := nat.ParsePortSpec(spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		for _, mapping := range mappings {
+			exposed[mapping.Port] = struct{}{}
+			bindings[mapping.Port] = append(bindings[mapping.Port], mapping.Binding)
+		}
+	}
+	for _, expose := range service.Expose {
+		proto, port := nat.SplitProtoPort(expose)
+		p, err := nat.NewPort(proto, port)
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", service.Name, err)
+		}
+		exposed[p] = struct{}{}
+	}
+	return exposed, bindings, nil
+}
+
+// serviceMounts converts the volumes of the service, named volumes are resolved to their project volume
+func (m *model) serviceMounts(service types.ServiceConfig) []mount.Mount {
+	mounts := make([]mount.Mount, 0, len(service.Volumes))
+	for _, volume := range service.Volumes {
+		mnt := mount.Mount{
+			Type:        mount.Type(volume.Type),
+			Source:      volume.Source,
+			Target:      volume.Target,
+			ReadOnly:    volume.ReadOnly,
+			Consistency: mount.Consistency(volume.Consistency),
+		}
+		switch volume.Type {
+		case types.VolumeTypeVolume:
+			if _, ok := m.project.Volumes[volume.Source]; ok {
+				mnt.Source = m.volumeName(volume.Source)
+			}
+			if volume.Volume != nil {
+				mnt.VolumeOptions = &mount.VolumeOptions{NoCopy: volume.Volume.NoCopy, Subpath: volume.Volume.Subpath}
+			}
+		case types.VolumeTypeBind:
+			if !filepath.IsAbs(mnt.Source) {
+				mnt.Source = filepath.Join(m.workingDir, mnt.Source)
+			}
+			if volume.Bind != nil {
+				mnt.BindOptions = &mount.BindOptions{
+					Propagation:      mount.Propagation(volume.Bind.Propagation),
+					CreateMountpoint: volume.Bind.CreateHostPath,
+				}
+			}
+		case types.VolumeTypeTmpfs:
+			if volume.Tmpfs != nil {
+				mnt.TmpfsOptions = &mount.TmpfsOptions{
+					SizeBytes: int64(volume.Tmpfs.Size),
+					Mode:      os.FileMode(volume.Tmpfs.Mode),
+				}
+			}
+		}
+		mounts = append(mounts, mnt)
+	}
+	return mounts
+}
+
+// fileMounts bind mounts the secrets of the service read only at /run/secrets/<target> and its configs at /<target>,
+// the target defaults to the name of the secret or config. See unsupportedFields for the secrets and configs that cannot be mounted.
+func (m *model) fileMounts(service types.ServiceConfig) []mount.Mount {
+	mounts := make([]mount.Mount, 0, len(service.Secrets)+len(service.Configs))
+	for _, secret := range service.Secrets {
+		target := secret.Target
+		if target == "" {
+			target = secret.Source
+		}
+		if !path.IsAbs(target) {
+			target = path.Join("/run/secrets", target)
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.project.Secrets[secret.Source].File,
+			Target:   target,
+			ReadOnly: true,
+		})
+	}
+	for _, config := range service.Configs {
+		target := config.Target
+		if target == "" {
+			target = config.Source
+		}
+		mounts = append(mounts, mount.Mount{
+			Type:     mount.TypeBind,
+			Source:   m.project.Configs[config.Source].File,
+			Target:   path.Join("/", target),
+			ReadOnly: true,
+		})
+	}
+	return mounts
+}
+
+// deployResources applies the limits and reservations of deploy.resources like docker compose,
+// e.g. limits.memory sets the memory limit like mem_limit
+func deployResources(resources types.Resources, out *container.Resources) {
+	if limits := resources.Limits; limits != nil {
+		if limits.MemoryBytes != 0 {
+			out.Memory = int64(limits.MemoryBytes)
+		}
+		if limits.NanoCPUs != 0 {
+			out.NanoCPUs = int64(float64(limits.NanoCPUs) * 1e9)
+		}
+		if limits.Pids != 0 {
+			pids := limits.Pids
+			out.PidsLimit = &pids
+		}
+	}
+	if reservations := resources.Reservations; reservations != nil {
+		if reservations.MemoryBytes != 0 {
+			out.MemoryReservation = int64(reservations.MemoryBytes)
+		}
+		for _, device := range reservations.Devices {
+			out.DeviceRequests = append(out.DeviceRequests, container.DeviceRequest{
+				Driver:       device.Driver,
+				Count:        int(device.Count),
+				DeviceIDs:    device.IDs,
+				Capabilities: [][]string{device.Capabilities},
+				Options:      device.Options,
+			})
+		}
+	}
+}
+
+// endpointSettings returns the settings of the service on one of its networks, the service name is always an alias
+func endpointSettings(service types.ServiceConfig, config *types.ServiceNetworkConfig) *network.EndpointSettings {
+	settings := &network.EndpointSettings{Aliases: []string{service.Name}}
+	if config == nil {
+		return settings
+	}
+	settings.Aliases = append(settings.Aliases, config.Aliases...)
+	settings.MacAddress = config.MacAddress
+	settings.DriverOpts = config.DriverOpts
+	settings.GwPriority = config.GatewayPriority
+	if config.Ipv4Address != "" || config.Ipv6Address != "" || len(config.LinkLocalIPs) > 0 {
+		settings.IPAMConfig = &network.EndpointIPAMConfig{
+			IPv4Address:  config.Ipv4Address,
+			IPv6Address:  config.Ipv6Address,
+			LinkLocalIPs: config.LinkLocalIPs,
+		}
+	}
+	return settings
+}
+
+// healthConfig converts the healthcheck of the service
+func healthConfig(check *types.HealthCheckConfig) *container.HealthConfig {
+	if check == nil {
+		return nil
+	}
+	if check.Disable {
+		return &container.HealthConfig{Test: []string{"NONE"}}
+	}
+	health := &container.HealthConfig{Test: check.Test}
+	if check.Interval != nil {
+		health.Interval = time.Duration(*check.Interval)
+	}
+	if check.Timeout != nil {
+		health.Timeout = time.Duration(*check.Timeout)
+	}
+	if check.StartPeriod != nil {
+		health.StartPeriod = time.Duration(*check.StartPeriod)
+	}
+	if check.StartInterval != nil {
+		health.StartInterval = time.Duration(*check.StartInterval)
+	}
+	if check.Retries != nil {
+		health.Retries = int(*check.Retries)
+	}
+	return health
+}
+
+// restartPolicy parses a compose restart value, e.g. "on-failure:3"
+func restartPolicy(restart string) container.RestartPolicy {
+	name, count, _ := strings.Cut(restart, ":")
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(name)}
+	if n, err := strconv.Atoi(count); err == nil {
+		policy.MaximumRetryCount = n
+	}
+	return policy
+}
+
+// serviceTmpfs converts "path:options" tmpfs entries
+func serviceTmpfs(tmpfs types.StringList) map[string]string {
+	if len(tmpfs) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(tmpfs))
+	for _, entry := range tmpfs {
+		path, options, _ := strings.Cut(entry, ":")
+		out[path] = options
+	}
+	return out
+}
+
+// serviceUlimits converts the ulimits of the service, sorted by name
+func serviceUlimits(ulimits map[string]*types.UlimitsConfig) []*container.Ulimit {
+	out := make([]*container.Ulimit, 0, len(ulimits))
+	for name, limit := range ulimits {
+		if limit == nil {
+			continue
+		}
+		soft, hard := limit.Soft, limit.Hard
+		if limit.Single != 0 {
+			soft, hard = limit.Single, limit.Single
+		}
+		out = append(out, &container.Ulimit{Name: name, Soft: int64(soft), Hard: int64(hard)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// servicePlatform parses "os/arch[/variant]", a value without a slash is the architecture
+func servicePlatform(platform string) *ocispec.Platform {
+	if platform == "" {
+		return nil
+	}
+	parts := strings.Split(platform, "/")
+	if len(parts) == 1 {
+		return &ocispec.Platform{Architecture: parts[0]}
+	}
+	p := &ocispec.Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) > 2 {
+		p.Variant = parts[2]
+	}
+	return p
+}
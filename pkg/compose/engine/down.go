@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	cerrdefs "github.com/containerd/errdefs"
+)
+
+// Down stops and removes the containers and networks of the project by their project label, like docker compose down.
+// It also removes containers created for the project by the docker compose cli.
+//
+// Supported options are remove orphans, timeout, volumes and writer. Removing images is not supported.
+func (e *Engine) Down(ctx context.Context, setters ...compose.SetComposeDownOption) error {
+	opt := &compose.ComposeDownOptions{
+		Flags:  []string{"down"},
+		Writer: os.Stdout,
+	}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return compose.NewComposeDownError(err)
+		}
+	}
+	if opt.RemoveImage != nil {
+		return compose.NewComposeDownError(compose.NewComposeFlagError("--rmi", "removing images is not supported by the engine"))
+	}
+	// down always covers every service, whatever profile it belongs to
+	m, err := e.model(ctx, []string{"*"})
+	if err != nil {
+		return compose.NewComposeDownError(err)
+	}
+	if err := e.down(ctx, m, opt); err != nil {
+		return compose.NewComposeDownError(err)
+	}
+	return nil
+}
+
+func (e *Engine) down(ctx context.Context, m *model, opt *compose.ComposeDownOptions) error {
+	containers, err := e.projectContainers(ctx, m.project.Name)
+	if err != nil {
+		return err
+	}
+	// remove dependents before their dependencies, orphans first
+	rank := map[string]int{}
+	for i, name := range m.order {
+		rank[name] = len(m.order) - i
+	}
+	removable := []response.ContainerSummary{}
+	for _, c := range containers {
+		if _, ok := rank[c.Labels[LabelService]]; !ok && !opt.RemoveOrphans {
+			continue
+		}
+		removable = append(removable, c)
+	}
+	sort.SliceStable(removable, func(i, j int) bool {
+		ri, rj := rank[removable[i].Labels[LabelService]], rank[removable[j].Labels[LabelService]]
+		if ri != rj {
+			return ri < rj
+		}
+		return containerNumber(removable[i]) > containerNumber(removable[j])
+	})
+	for _, c := range removable {
+		if err := e.removeContainer(ctx, c, opt.Timeout, opt.RemoveVolumes); err != nil {
+			return err
+		}
+		report(opt.Writer, "Container", containerSummaryName(c), "Removed")
+	}
+
+	keys := m.networks()
+	for key := range m.project.Networks {
+		if !slices.Contains(keys, key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if m.project.Networks[key].External {
+			continue
+		}
+		name := m.networkName(key)
+		if err := e.api.NetworkRemove(ctx, name); err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to remove network %s: %w", name, err)
+		}
+		report(opt.Writer, "Network", name, "Removed")
+	}
+
+	if !opt.RemoveVolumes {
+		return nil
+	}
+	volumes := make([]string, 0, len(m.project.Volumes))
+	for key := range m.project.Volumes {
+		volumes = append(volumes, key)
+	}
+	sort.Strings(volumes)
+	for _, key := range volumes {
+		if m.project.Volumes[key].External {
+			continue
+		}
+		name := m.volumeName(key)
+		if err := e.api.VolumeRemove(ctx, name, false); err != nil {
+			if cerrdefs.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("failed to remove volume %s: %w", name, err)
+		}
+		report(opt.Writer, "Volume", name, "Removed")
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+// Package engine runs a create.Project directly against the docker Engine API, without the docker compose cli.
+//
+// It implements up, down, ps and logs on top of pkg/client for environments that only provide a docker socket.
+// Resources are labeled like docker compose labels them, so projects started by the engine can be inspected or torn down
+// with the docker compose cli and vice versa. The project is loaded like the docker compose cli loads it,
+// variables are interpolated with the os environment and the .env file of the project directory.
+// Config changes are detected with the same config hash as docker compose computes it.
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/client"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/list"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/logs"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/remove"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/start"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/stop"
+	"github.com/aptd3v/go-contain/pkg/client/options/image/pull"
+	netcreate "github.com/aptd3v/go-contain/pkg/client/options/network/create"
+	"github.com/aptd3v/go-contain/pkg/client/options/network/inspect"
+	volcreate "github.com/aptd3v/go-contain/pkg/client/options/volume/create"
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/aptd3v/go-contain/pkg/create"
+)
+
+// Labels set by docker compose on the resources of a project
+const (
+	LabelProject         = "com.docker.compose.project"
+	LabelWorkingDir      = "com.docker.compose.project.working_dir"
+	LabelService         = "com.docker.compose.service"
+	LabelConfigHash      = "com.docker.compose.config-hash"
+	LabelContainerNumber = "com.docker.compose.container-number"
+	LabelOneoff          = "com.docker.compose.oneoff"
+	LabelDependsOn       = "com.docker.compose.depends_on"
+	LabelNetwork         = "com.docker.compose.network"
+	LabelVolume          = "com.docker.compose.volume"
+)
+
+// DefaultPollInterval is the default interval at which container state is polled while waiting for dependencies
+const DefaultPollInterval = 500 * time.Millisecond
+
+// dockerAPI is the subset of *client.Client used by the engine
+type dockerAPI interface {
+	ContainerList(ctx context.Context, setters ...list.SetContainerListOption) ([]response.ContainerSummary, error)
+	ContainerCreate(ctx context.Context, created *create.Container) (*response.ContainerCreate, error)
+	ContainerStart(ctx context.Context, id string, setters ...start.SetContainerStartOption) error
+	ContainerStop(ctx context.Context, id string, setters ...stop.SetContainerStopOption) error
+	ContainerRemove(ctx context.Context, id string, setters ...remove.SetContainerRemoveOption) error
+	ContainerInspect(ctx context.Context, id string) (*response.ContainerInspect, error)
+	ContainerLogs(ctx context.Context, id string, setters ...logs.SetContainerLogsOption) (io.ReadCloser, error)
+	ImageInspect(ctx context.Context, ref string) (*response.ImageInspect, error)
+	ImagePull(ctx context.Context, ref string, setters ...pull.SetImagePullOption) (io.ReadCloser, error)
+	NetworkInspect(ctx context.Context, networkID string, setters ...inspect.SetNetworkInspectOption) (*response.NetworkInspect, error)
+	NetworkCreate(ctx context.Context, name string, setters ...netcreate.SetNetworkCreateOption) (*response.NetworkCreate, error)
+	NetworkRemove(ctx context.Context, networkID string) error
+	VolumeInspect(ctx context.Context, name string) (*response.Volume, error)
+	VolumeCreate(ctx context.Context, setters ...volcreate.SetVolumeCreateOption) (*response.Volume, error)
+	VolumeRemove(ctx context.Context, name string, force bool) error
+}
+
+var _ dockerAPI = (*client.Client)(nil)
+
+// Engine runs a project with the docker Engine API
+type Engine struct {
+	project      *create.Project
+	api          dockerAPI
+	pollInterval time.Duration
+	errs         []error
+}
+
+// SetEngineOption is a function that configures an Engine
+type SetEngineOption func(*Engine) error
+
+// NewEngine creates a new engine for the given project that talks to the docker daemon through cli.
+func NewEngine(project *create.Project, cli *client.Client, setters ...SetEngineOption) *Engine {
+	return newEngine(project, cli, setters...)
+}
+
+func newEngine(project *create.Project, api dockerAPI, setters ...SetEngineOption) *Engine {
+	e := &Engine{
+		project:      project,
+		api:          api,
+		pollInterval: DefaultPollInterval,
+	}
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(e); err != nil {
+			e.errs = append(e.errs, err)
+		}
+	}
+	return e
+}
+
+// WithPollInterval sets how often container state is polled while waiting for
+// depends_on conditions and up.WithWait (default: DefaultPollInterval)
+func WithPollInterval(interval time.Duration) SetEngineOption {
+	return func(e *Engine) error {
+		if interval <= 0 {
+			return compose.NewComposeError(fmt.Errorf("WithPollInterval: interval must be positive"))
+		}
+		e.pollInterval = interval
+		return nil
+	}
+}
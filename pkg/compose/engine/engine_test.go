@@ -0,0 +1,836 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/client/options/container/list"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/logs"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/remove"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/start"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/stop"
+	"github.com/aptd3v/go-contain/pkg/client/options/image/pull"
+	netcreate "github.com/aptd3v/go-contain/pkg/client/options/network/create"
+	"github.com/aptd3v/go-contain/pkg/client/options/network/inspect"
+	volcreate "github.com/aptd3v/go-contain/pkg/client/options/volume/create"
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/aptd3v/go-contain/pkg/compose/options/down"
+	logsopt "github.com/aptd3v/go-contain/pkg/compose/options/logs"
+	"github.com/aptd3v/go-contain/pkg/compose/options/ps"
+	"github.com/aptd3v/go-contain/pkg/compose/options/up"
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc/health"
+	"github.com/aptd3v/go-contain/pkg/create/config/load"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc"
+	"github.com/compose-spec/compose-go/v2/types"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeContainer is a container of the fake daemon
+type fakeContainer struct {
+	id      string
+	name    string
+	config  *container.Config
+	host    *container.HostConfig
+	state   container.ContainerState
+	health  string
+	inspect int
+}
+
+// fakeDaemon is an in memory dockerAPI, ops records the mutating calls in order
+type fakeDaemon struct {
+	mu         sync.Mutex
+	containers map[string]*fakeContainer
+	networks   map[string]bool
+	volumes    map[string]bool
+	images     map[string]bool
+	logs       map[string][]byte
+	ops        []string
+}
+
+var _ dockerAPI = (*fakeDaemon)(nil)
+
+func newFakeDaemon() *fakeDaemon {
+	return &fakeDaemon{
+		containers: map[string]*fakeContainer{},
+		networks:   map[string]bool{},
+		volumes:    map[string]bool{},
+		images:     map[string]bool{},
+		logs:       map[string][]byte{},
+	}
+}
+
+func (f *fakeDaemon) record(format string, args ...any) {
+	f.ops = append(f.ops, fmt.Sprintf(format, args...))
+}
+
+func notFound(kind, name string) error {
+	return fmt.Errorf("no such %s: %s: %w", kind, name, cerrdefs.ErrNotFound)
+}
+
+func (f *fakeDaemon) find(id string) (*fakeContainer, error) {
+	c, ok := f.containers[id]
+	if !ok {
+		return nil, notFound("container", id)
+	}
+	return c, nil
+}
+
+func (f *fakeDaemon) ContainerList(_ context.Context, setters ...list.SetContainerListOption) ([]response.ContainerSummary, error) {
+	opt := container.ListOptions{Filters: filters.NewArgs()}
+	for _, setter := range setters {
+		if err := setter(&opt); err != nil {
+			return nil, err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := []response.ContainerSummary{}
+	for _, c := range f.containers {
+		if !opt.All && c.state != container.StateRunning {
+			continue
+		}
+		matches := true
+		for _, label := range opt.Filters.Get("label") {
+			key, value, _ := strings.Cut(label, "=")
+			if c.config.Labels[key] != value {
+				matches = false
+			}
+		}
+		if !matches {
+			continue
+		}
+		status := "Up 2 seconds"
+		if c.health != "" {
+			status += " (" + c.health + ")"
+		}
+		if c.state != container.StateRunning {
+			status = "Exited (0) 2 seconds ago"
+		}
+		result = append(result, response.ContainerSummary{Summary: container.Summary{
+			ID:     c.id,
+			Names:  []string{"/" + c.name},
+			Image:  c.config.Image,
+			Labels: c.config.Labels,
+			State:  c.state,
+			Status: status,
+		}})
+	}
+	return result, nil
+}
+
+func (f *fakeDaemon) ContainerCreate(_ context.Context, created *create.Container) (*response.ContainerCreate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := "id-" + created.Name
+	if _, ok := f.containers[id]; ok {
+		return nil, fmt.Errorf("container name %s is already in use", created.Name)
+	}
+	f.containers[id] = &fakeContainer{id: id, name: created.Name, config: created.Config.Container, host: created.Config.Host, state: container.StateCreated}
+	f.record("create %s", created.Name)
+	return &response.ContainerCreate{CreateResponse: container.CreateResponse{ID: id}}, nil
+}
+
+func (f *fakeDaemon) ContainerStart(_ context.Context, id string, _ ...start.SetContainerStartOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, err := f.find(id)
+	if err != nil {
+		return err
+	}
+	c.state = container.StateRunning
+	if c.config.Healthcheck != nil {
+		c.health = container.Starting
+	}
+	f.record("start %s", c.name)
+	return nil
+}
+
+func (f *fakeDaemon) ContainerStop(_ context.Context, id string, _ ...stop.SetContainerStopOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, err := f.find(id)
+	if err != nil {
+		return err
+	}
+	c.state = container.StateExited
+	f.record("stop %s", c.name)
+	return nil
+}
+
+func (f *fakeDaemon) ContainerRemove(_ context.Context, id string, _ ...remove.SetContainerRemoveOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, err := f.find(id)
+	if err != nil {
+		return err
+	}
+	delete(f.containers, id)
+	f.record("remove %s", c.name)
+	return nil
+}
+
+// ContainerInspect reports a container with a healthcheck as healthy from its second inspection
+func (f *fakeDaemon) ContainerInspect(_ context.Context, id string) (*response.ContainerInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	c, err := f.find(id)
+	if err != nil {
+		return nil, err
+	}
+	c.inspect++
+	if c.health == container.Starting && c.inspect > 1 {
+		c.health = container.Healthy
+	}
+	state := &container.State{Status: c.state, Running: c.state == container.StateRunning}
+	if c.health != "" {
+		state.Health = &container.Health{Status: c.health}
+	}
+	return &response.ContainerInspect{InspectResponse: container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{ID: c.id, Name: "/" + c.name, State: state},
+		Config:            c.config,
+	}}, nil
+}
+
+func (f *fakeDaemon) ContainerLogs(_ context.Context, id string, _ ...logs.SetContainerLogsOption) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, err := f.find(id); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(f.logs[id])), nil
+}
+
+func (f *fakeDaemon) ImageInspect(_ context.Context, ref string) (*response.ImageInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.images[ref] {
+		return nil, notFound("image", ref)
+	}
+	return &response.ImageInspect{InspectResponse: image.InspectResponse{ID: ref}}, nil
+}
+
+func (f *fakeDaemon) ImagePull(_ context.Context, ref string, _ ...pull.SetImagePullOption) (io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.images[ref] = true
+	f.record("pull %s", ref)
+	return io.NopCloser(strings.NewReader(`{"status":"Pull complete"}`)), nil
+}
+
+func (f *fakeDaemon) NetworkInspect(_ context.Context, name string, _ ...inspect.SetNetworkInspectOption) (*response.NetworkInspect, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.networks[name] {
+		return nil, notFound("network", name)
+	}
+	return &response.NetworkInspect{Inspect: network.Inspect{Name: name}}, nil
+}
+
+func (f *fakeDaemon) NetworkCreate(_ context.Context, name string, setters ...netcreate.SetNetworkCreateOption) (*response.NetworkCreate, error) {
+	opt := network.CreateOptions{}
+	for _, setter := range setters {
+		if err := setter(&opt); err != nil {
+			return nil, err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.networks[name] = true
+	f.record("create network %s", name)
+	return &response.NetworkCreate{CreateResponse: network.CreateResponse{ID: name}}, nil
+}
+
+func (f *fakeDaemon) NetworkRemove(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.networks[name] {
+		return notFound("network", name)
+	}
+	delete(f.networks, name)
+	f.record("remove network %s", name)
+	return nil
+}
+
+func (f *fakeDaemon) VolumeInspect(_ context.Context, name string) (*response.Volume, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.volumes[name] {
+		return nil, notFound("volume", name)
+	}
+	return &response.Volume{Volume: volume.Volume{Name: name}}, nil
+}
+
+func (f *fakeDaemon) VolumeCreate(_ context.Context, setters ...volcreate.SetVolumeCreateOption) (*response.Volume, error) {
+	opt := volume.CreateOptions{}
+	for _, setter := range setters {
+		if err := setter(&opt); err != nil {
+			return nil, err
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volumes[opt.Name] = true
+	f.record("create volume %s", opt.Name)
+	return &response.Volume{Volume: volume.Volume{Name: opt.Name}}, nil
+}
+
+func (f *fakeDaemon) VolumeRemove(_ context.Context, name string, _ bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.volumes[name] {
+		return notFound("volume", name)
+	}
+	delete(f.volumes, name)
+	f.record("remove volume %s", name)
+	return nil
+}
+
+// demoProject is a web service that depends on a healthy db with a named volume
+func demoProject() *create.Project {
+	return create.NewProject("demo").
+		WithService("web", create.NewContainer().
+			WithContainerConfig(cc.WithImage("nginx:alpine")),
+			sc.WithDependsOnHealthy("db"),
+		).
+		WithService("db", create.NewContainer().
+			WithContainerConfig(
+				cc.WithImage("postgres:16"),
+				cc.WithHealthCheck(health.WithTest("CMD", "pg_isready")),
+			),
+		).
+		WithVolume("data")
+}
+
+func TestEngineUpDown(t *testing.T) {
+	daemon := newFakeDaemon()
+	e := newEngine(demoProject(), daemon, WithPollInterval(time.Millisecond))
+	ctx := context.Background()
+	out := &bytes.Buffer{}
+
+	err := e.Up(ctx, up.WithDetach(), up.WithWriter(out))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, []string{
+		"create network demo_default",
+		"pull postgres:16",
+		"create demo-db-1",
+		"start demo-db-1",
+		"pull nginx:alpine",
+		"create demo-web-1",
+		"start demo-web-1",
+	}, daemon.ops)
+	assert.Contains(t, out.String(), "Container demo-web-1  Started\n")
+
+	web := daemon.containers["id-demo-web-1"]
+	if assert.NotNil(t, web) {
+		assert.Equal(t, "demo", web.config.Labels[LabelProject])
+		assert.Equal(t, "web", web.config.Labels[LabelService])
+		assert.Equal(t, "1", web.config.Labels[LabelContainerNumber])
+		assert.Equal(t, "False", web.config.Labels[LabelOneoff])
+		assert.True(t, strings.HasPrefix(web.config.Labels[LabelDependsOn], "db:service_healthy:"))
+		assert.NotEmpty(t, web.config.Labels[LabelConfigHash])
+	}
+	// web was only created once db reported healthy
+	assert.Equal(t, container.Healthy, daemon.containers["id-demo-db-1"].health)
+
+	// a second up keeps the unchanged containers
+	daemon.ops = nil
+	assert.NoError(t, e.Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	assert.Empty(t, daemon.ops)
+
+	assert.NoError(t, e.Down(ctx, down.WithWriter(io.Discard), down.WithRemoveVolumes()))
+	assert.Equal(t, []string{
+		"stop demo-web-1",
+		"remove demo-web-1",
+		"stop demo-db-1",
+		"remove demo-db-1",
+		"remove network demo_default",
+	}, daemon.ops)
+	assert.Empty(t, daemon.containers)
+	assert.Empty(t, daemon.networks)
+}
+
+func TestEngineUpRecreate(t *testing.T) {
+	daemon := newFakeDaemon()
+	ctx := context.Background()
+	project := create.NewProject("demo").
+		WithService("web", create.NewContainer().WithContainerConfig(cc.WithImage("nginx:alpine")))
+	assert.NoError(t, newEngine(project, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+
+	changed := create.NewProject("demo").
+		WithService("web", create.NewContainer().WithContainerConfig(cc.WithImage("nginx:alpine"), cc.WithCommand("nginx", "-g", "daemon off;")))
+	daemon.ops = nil
+	assert.NoError(t, newEngine(changed, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	assert.Equal(t, []string{
+		"stop demo-web-1",
+		"remove demo-web-1",
+		"create demo-web-1",
+		"start demo-web-1",
+	}, daemon.ops)
+
+	// containers created by the docker compose cli with the same config carry the same hash and are kept
+	web := daemon.containers["id-demo-web-1"]
+	hash := web.config.Labels[LabelConfigHash]
+	web.config.Labels = map[string]string{
+		LabelProject:         "demo",
+		LabelService:         "web",
+		LabelContainerNumber: "1",
+		LabelOneoff:          "False",
+		LabelConfigHash:      hash,
+	}
+	daemon.ops = nil
+	assert.NoError(t, newEngine(changed, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	assert.Empty(t, daemon.ops)
+}
+
+func TestEngineUpConfigHashFixture(t *testing.T) {
+	// the hashes printed by `docker compose -f testdata/hash.yaml config --hash "*"`
+	want := map[string]string{
+		"db":  "61de89996943365e22f2843934969b977041fc21bbacfd02c4cbb045ec645c39",
+		"web": "f3bb7d6da98e1461ecfa81ecc9aff5dc3d5044028a044ba44e408485dc81b405",
+	}
+	ctx := context.Background()
+	project, err := create.LoadProject(ctx, []string{filepath.Join("testdata", "hash.yaml")}, load.WithoutOsEnv())
+	if !assert.NoError(t, err) {
+		return
+	}
+	daemon := newFakeDaemon()
+	if !assert.NoError(t, newEngine(project, daemon, WithPollInterval(time.Millisecond)).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard))) {
+		return
+	}
+	for service, hash := range want {
+		c := daemon.containers["id-demo-"+service+"-1"]
+		if assert.NotNil(t, c, service) {
+			assert.Equal(t, hash, c.config.Labels[LabelConfigHash], service)
+		}
+	}
+}
+
+func TestEngineUpInterpolation(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+name: app
+services:
+  web:
+    image: nginx:${NGINX_TAG}
+    command: echo $$HOME
+    environment:
+      PASSWORD: ${PASSWORD}
+`
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	project, err := create.LoadProject(ctx, []string{filepath.Join(dir, "compose.yaml")},
+		load.WithoutOsEnv(), load.WithEnv("NGINX_TAG", "1.27"), load.WithEnv("PASSWORD", "pa$word"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	daemon := newFakeDaemon()
+	assert.NoError(t, newEngine(project, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	web := daemon.containers["id-app-web-1"]
+	if assert.NotNil(t, web) {
+		assert.Equal(t, "nginx:1.27", web.config.Image)
+		assert.Equal(t, []string{"echo", "$HOME"}, []string(web.config.Cmd))
+		assert.Contains(t, web.config.Env, "PASSWORD=pa$word")
+		assert.Equal(t, dir, web.config.Labels[LabelWorkingDir])
+	}
+
+	// projects created in go are interpolated by the engine like docker compose interpolates them
+	t.Setenv("WORKER_TAG", "3.20")
+	built := create.NewProject("app").
+		WithService("worker", create.NewContainer().WithContainerConfig(cc.WithImage("alpine:${WORKER_TAG}"), cc.WithCommand("echo", "$$HOME")))
+	assert.NoError(t, newEngine(built, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard), up.WithRemoveOrphans()))
+	worker := daemon.containers["id-app-worker-1"]
+	if assert.NotNil(t, worker) {
+		assert.Equal(t, "alpine:3.20", worker.config.Image)
+		assert.Equal(t, []string{"echo", "$HOME"}, []string(worker.config.Cmd))
+	}
+}
+
+func TestEngineUpFileMounts(t *testing.T) {
+	dir := t.TempDir()
+	content := `
+name: app
+services:
+  web:
+    image: nginx:alpine
+    secrets:
+      - token
+      - source: token
+        target: /etc/token
+    configs:
+      - nginx
+      - source: nginx
+        target: conf.d/default.conf
+    deploy:
+      resources:
+        limits:
+          cpus: "0.5"
+          memory: 128m
+          pids: 100
+        reservations:
+          memory: 32m
+          devices:
+            - driver: nvidia
+              count: 1
+              capabilities: [gpu]
+secrets:
+  token:
+    file: ./token.txt
+configs:
+  nginx:
+    file: ./nginx.conf
+`
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	project, err := create.LoadProject(ctx, []string{filepath.Join(dir, "compose.yaml")}, load.WithoutOsEnv())
+	if !assert.NoError(t, err) {
+		return
+	}
+	daemon := newFakeDaemon()
+	assert.NoError(t, newEngine(project, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	web := daemon.containers["id-app-web-1"]
+	if !assert.NotNil(t, web) {
+		return
+	}
+	token, nginx := filepath.Join(dir, "token.txt"), filepath.Join(dir, "nginx.conf")
+	assert.Equal(t, []mount.Mount{
+		{Type: mount.TypeBind, Source: token, Target: "/run/secrets/token", ReadOnly: true},
+		{Type: mount.TypeBind, Source: token, Target: "/etc/token", ReadOnly: true},
+		{Type: mount.TypeBind, Source: nginx, Target: "/nginx", ReadOnly: true},
+		{Type: mount.TypeBind, Source: nginx, Target: "/conf.d/default.conf", ReadOnly: true},
+	}, web.host.Mounts)
+	assert.Equal(t, int64(128*1024*1024), web.host.Memory)
+	assert.Equal(t, int64(32*1024*1024), web.host.MemoryReservation)
+	assert.Equal(t, int64(5e8), web.host.NanoCPUs)
+	if assert.NotNil(t, web.host.PidsLimit) {
+		assert.Equal(t, int64(100), *web.host.PidsLimit)
+	}
+	assert.Equal(t, []container.DeviceRequest{
+		{Driver: "nvidia", Count: 1, Capabilities: [][]string{{"gpu"}}},
+	}, web.host.DeviceRequests)
+}
+
+func TestEngineUpUnsupportedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		field   string
+	}{
+		{
+			name: "secret from environment",
+			content: `
+services:
+  web:
+    image: nginx:alpine
+    secrets: [token]
+secrets:
+  token:
+    environment: TOKEN
+`,
+			field: "secrets.token.environment",
+		},
+		{
+			name: "config from content",
+			content: `
+services:
+  web:
+    image: nginx:alpine
+    configs: [nginx]
+configs:
+  nginx:
+    content: "server {}"
+`,
+			field: "configs.nginx.content",
+		},
+		{
+			name: "secret mode",
+			content: `
+services:
+  web:
+    image: nginx:alpine
+    secrets:
+      - source: token
+        mode: 0400
+secrets:
+  token:
+    file: ./token.txt
+`,
+			field: "secrets.token.mode",
+		},
+		{
+			name: "reserved cpus",
+			content: `
+services:
+  web:
+    image: nginx:alpine
+    deploy:
+      resources:
+        reservations:
+          cpus: "0.25"
+`,
+			field: "deploy.resources.reservations.cpus",
+		},
+	}
+	ctx := context.Background()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte("name: app\n"+tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			project, err := create.LoadProject(ctx, []string{filepath.Join(dir, "compose.yaml")}, load.WithoutOsEnv())
+			if !assert.NoError(t, err) {
+				return
+			}
+			daemon := newFakeDaemon()
+			err = newEngine(project, daemon).Up(ctx, up.WithDetach(), up.WithWriter(io.Discard))
+			var composeErr *compose.ComposeError
+			assert.ErrorAs(t, err, &composeErr)
+			assert.ErrorContains(t, err, "service web: "+tt.field+" is not supported by the engine")
+			assert.Empty(t, daemon.ops)
+		})
+	}
+}
+
+func TestEngineUpUnsupported(t *testing.T) {
+	tests := []struct {
+		name    string
+		setters []compose.SetComposeUpOption
+		flag    string
+	}{
+		{"build", []compose.SetComposeUpOption{up.WithDetach(), up.WithBuild()}, "--build"},
+		{"attached", nil, "--detach"},
+		{"no deps", []compose.SetComposeUpOption{up.WithDetach(), up.WithNoDeps()}, "--no-deps"},
+		{"abort on container exit", []compose.SetComposeUpOption{up.WithAbortOnContainerExit()}, "--abort-on-container-exit"},
+		{"abort on container failure", []compose.SetComposeUpOption{up.WithAbortOnContainerFailure()}, "--abort-on-container-failure"},
+		{"exit code from", []compose.SetComposeUpOption{up.WithWait(), up.WithExitCodeFrom("web")}, "--exit-code-from"},
+		{"always recreate deps", []compose.SetComposeUpOption{up.WithDetach(), up.WithAlwaysRecreateDeps()}, "--always-recreate-deps"},
+		{"renew anon volumes", []compose.SetComposeUpOption{up.WithDetach(), up.WithRenewAnonVolumes()}, "--renew-anon-volumes"},
+		{"watch", []compose.SetComposeUpOption{up.WithNoStart(), up.WithWatch()}, "--watch"},
+		{"attach", []compose.SetComposeUpOption{up.WithNoStart(), up.WithAttach("web")}, "--attach"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daemon := newFakeDaemon()
+			err := newEngine(demoProject(), daemon).Up(context.Background(), tt.setters...)
+			assert.True(t, compose.IsComposeUpError(err))
+			assert.True(t, compose.IsComposeFlagError(err))
+			assert.ErrorContains(t, err, tt.flag)
+			assert.Empty(t, daemon.ops)
+		})
+	}
+
+	// cosmetic options are ignored
+	daemon := newFakeDaemon()
+	e := newEngine(demoProject(), daemon, WithPollInterval(time.Millisecond))
+	assert.NoError(t, e.Up(context.Background(), up.WithDetach(), up.WithNoColor(), up.WithTimestamps(), up.WithMenu(), up.WithWriter(io.Discard)))
+	assert.NotEmpty(t, daemon.ops)
+}
+
+func TestEnginePsList(t *testing.T) {
+	daemon := newFakeDaemon()
+	e := newEngine(demoProject(), daemon, WithPollInterval(time.Millisecond))
+	ctx := context.Background()
+	assert.NoError(t, e.Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+
+	containers, err := e.PsList(ctx, ps.WithServiceNames("db"))
+	if !assert.NoError(t, err) || !assert.Len(t, containers, 1) {
+		return
+	}
+	assert.Equal(t, "demo-db-1", containers[0].Name)
+	assert.Equal(t, "db", containers[0].Service)
+	assert.Equal(t, "demo", containers[0].Project)
+	assert.Equal(t, "healthy", containers[0].Health)
+
+	_, err = e.PsList(ctx, ps.WithQuiet())
+	assert.True(t, compose.IsComposePsError(err))
+}
+
+func TestServiceContainer(t *testing.T) {
+	sc := serviceContainer(response.ContainerSummary{Summary: container.Summary{
+		ID:     "abc",
+		Names:  []string{"/demo-web-1"},
+		Labels: map[string]string{LabelProject: "demo", LabelService: "web"},
+		State:  container.StateExited,
+		Status: "Exited (137) 5 seconds ago",
+		Ports: []container.Port{
+			{IP: "0.0.0.0", PrivatePort: 80, PublicPort: 8080, Type: "tcp"},
+			{PrivatePort: 443, Type: "tcp"},
+		},
+	}})
+	assert.Equal(t, "demo-web-1", sc.Name)
+	assert.Equal(t, 137, sc.ExitCode)
+	assert.Equal(t, "", sc.Health)
+	assert.Equal(t, "0.0.0.0:8080->80/tcp, 443/tcp", sc.Ports)
+	assert.Equal(t, []compose.Publisher{
+		{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 8080, Protocol: "tcp"},
+		{TargetPort: 443, Protocol: "tcp"},
+	}, sc.Publishers)
+
+	sc = serviceContainer(response.ContainerSummary{Summary: container.Summary{Status: "Up 3 minutes (health: starting)"}})
+	assert.Equal(t, "starting", sc.Health)
+}
+
+func TestEngineLogStream(t *testing.T) {
+	daemon := newFakeDaemon()
+	e := newEngine(demoProject(), daemon, WithPollInterval(time.Millisecond))
+	ctx := context.Background()
+	assert.NoError(t, e.Up(ctx, up.WithDetach(), up.WithWriter(io.Discard)))
+	var buf bytes.Buffer
+	fmt.Fprint(stdcopy.NewStdWriter(&buf, stdcopy.Stdout), "2025-06-01T10:12:44.5Z ready\n")
+	fmt.Fprint(stdcopy.NewStdWriter(&buf, stdcopy.Stderr), "2025-06-01T10:12:45Z warning")
+	daemon.logs["id-demo-db-1"] = buf.Bytes()
+
+	lines, errCh, err := e.LogStream(ctx, logsopt.WithTimestamps())
+	if !assert.NoError(t, err) {
+		return
+	}
+	got := []compose.LogLine{}
+	for line := range lines {
+		got = append(got, line)
+	}
+	assert.NoError(t, <-errCh)
+	assert.Equal(t, []compose.LogLine{
+		{Service: "db", Container: "db-1", Stream: compose.LogStreamStdout, Timestamp: time.Date(2025, 6, 1, 10, 12, 44, 500000000, time.UTC), Message: "ready"},
+		{Service: "db", Container: "db-1", Stream: compose.LogStreamStderr, Timestamp: time.Date(2025, 6, 1, 10, 12, 45, 0, time.UTC), Message: "warning"},
+	}, got)
+}
+
+func TestServiceOrder(t *testing.T) {
+	services := types.Services{
+		"web":    {Name: "web", DependsOn: types.DependsOnConfig{"api": {Condition: types.ServiceConditionStarted, Required: true}}},
+		"api":    {Name: "api", DependsOn: types.DependsOnConfig{"db": {Condition: types.ServiceConditionHealthy, Required: true}, "cache": {Required: true}}},
+		"db":     {Name: "db"},
+		"cache":  {Name: "cache"},
+		"worker": {Name: "worker", DependsOn: types.DependsOnConfig{"metrics": {Required: false}}},
+	}
+	order, err := serviceOrder(services)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"cache", "db", "api", "web", "worker"}, order)
+
+	services["db"] = types.ServiceConfig{Name: "db", DependsOn: types.DependsOnConfig{"web": {Required: true}}}
+	_, err = serviceOrder(services)
+	assert.EqualError(t, err, "dependency cycle between services: [api db web]")
+
+	delete(services, "db")
+	_, err = serviceOrder(services)
+	assert.EqualError(t, err, "service api depends on db which is not enabled")
+
+	// network_mode, volumes_from and links are implicit dependencies
+	services = types.Services{
+		"app":     {Name: "app", NetworkMode: "service:vpn"},
+		"backup":  {Name: "backup", VolumesFrom: []string{"storage:ro", "container:external"}},
+		"client":  {Name: "client", Links: []string{"server:api"}},
+		"server":  {Name: "server"},
+		"storage": {Name: "storage"},
+		"vpn":     {Name: "vpn"},
+	}
+	order, err = serviceOrder(services)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"server", "client", "storage", "backup", "vpn", "app"}, order)
+
+	delete(services, "vpn")
+	_, err = serviceOrder(services)
+	assert.EqualError(t, err, "service app depends on vpn which is not enabled")
+}
+
+func TestServiceEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.env"), []byte("A=file\nB=file\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service := types.ServiceConfig{
+		Name: "web",
+		EnvFiles: []types.EnvFile{
+			{Path: "app.env", Required: true},
+			{Path: "missing.env", Required: false},
+		},
+		Environment: types.NewMappingWithEquals([]string{"B=service"}),
+	}
+	// env files are resolved against the working directory of the project, not the current directory
+	env, err := serviceEnvironment(service, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"A=file", "B=service"}, env)
+
+	service.EnvFiles[1].Required = true
+	_, err = serviceEnvironment(service, dir)
+	assert.ErrorContains(t, err, "env file "+filepath.Join(dir, "missing.env")+" not found")
+}
+
+func TestVolumesFrom(t *testing.T) {
+	m := &model{project: &types.Project{
+		Name:     "demo",
+		Services: types.Services{"db": {Name: "db"}, "named": {Name: "named", ContainerName: "storage"}},
+	}}
+	assert.Equal(t, []string{"demo-db-1:ro", "storage", "backup", "backup:rw"},
+		m.volumesFrom([]string{"db:ro", "named", "container:backup", "container:backup:rw"}))
+}
+
+func TestResourceNames(t *testing.T) {
+	m := &model{project: &types.Project{
+		Name: "demo",
+		Networks: types.Networks{
+			"front":  {},
+			"shared": {External: true},
+			"named":  {Name: "custom", External: true},
+		},
+		Volumes: types.Volumes{
+			"data":   {},
+			"backup": {External: true},
+		},
+	}}
+	assert.Equal(t, "demo_front", m.networkName("front"))
+	assert.Equal(t, "shared", m.networkName("shared"))
+	assert.Equal(t, "custom", m.networkName("named"))
+	assert.Equal(t, "demo_default", m.networkName("default"))
+	assert.Equal(t, "demo_data", m.volumeName("data"))
+	assert.Equal(t, "backup", m.volumeName("backup"))
+}
+
+func TestConfigHash(t *testing.T) {
+	service := types.ServiceConfig{Name: "web", Image: "nginx:alpine", Environment: types.NewMappingWithEquals([]string{"A=1"})}
+	hash, err := configHash(service)
+	assert.NoError(t, err)
+
+	// scale, depends_on and profiles do not change the container
+	scale := 3
+	same := service
+	same.Scale = &scale
+	same.DependsOn = types.DependsOnConfig{"db": {Condition: types.ServiceConditionStarted}}
+	same.Profiles = []string{"debug"}
+	sameHash, err := configHash(same)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	changed := service
+	changed.Image = "nginx:latest"
+	changedHash, err := configHash(changed)
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+	assert.False(t, slices.Contains([]string{hash, sameHash}, changedHash))
+}
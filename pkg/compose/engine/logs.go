@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/client/options/container/logs"
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// Logs writes the logs of the project containers to the writer, like docker compose logs.
+// Every line is prefixed with the container name unless logs.WithNoLogPrefix is used.
+func (e *Engine) Logs(ctx context.Context, setters ...compose.SetComposeLogsOption) error {
+	opt := &compose.ComposeLogsOptions{
+		Flags:  []string{"logs"},
+		Writer: os.Stdout,
+	}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return compose.NewComposeLogsError(err)
+		}
+	}
+	lines, errCh, err := e.LogStream(ctx, setters...)
+	if err != nil {
+		return err
+	}
+	for line := range lines {
+		message := line.Message
+		if opt.Timestamps && !line.Timestamp.IsZero() {
+			message = line.Timestamp.Format(time.RFC3339Nano) + " " + message
+		}
+		if !opt.NoLogPrefix {
+			message = line.Container + "  | " + message
+		}
+		fmt.Fprintln(opt.Writer, message)
+	}
+	return <-errCh
+}
+
+// LogStream streams the parsed log lines of the project containers, like (*compose).LogStream.
+// Lines of different containers are interleaved in the order they are read.
+// Both channels are closed when every log stream ended, use logs.WithFollow to keep streaming until the context is canceled.
+func (e *Engine) LogStream(ctx context.Context, setters ...compose.SetComposeLogsOption) (<-chan compose.LogLine, <-chan error, error) {
+	opt := &compose.ComposeLogsOptions{
+		Flags:  []string{"logs"},
+		Writer: io.Discard,
+	}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, nil, compose.NewComposeLogsError(err)
+		}
+	}
+	m, err := e.model(ctx, opt.Profiles)
+	if err != nil {
+		return nil, nil, compose.NewComposeLogsError(err)
+	}
+	containers, err := e.projectContainers(ctx, m.project.Name)
+	if err != nil {
+		return nil, nil, compose.NewComposeLogsError(err)
+	}
+	logOpts := []logs.SetContainerLogsOption{
+		logs.WithShowStdout(),
+		logs.WithShowStderr(),
+	}
+	if opt.Follow {
+		logOpts = append(logOpts, logs.WithFollow())
+	}
+	if opt.Tail != nil {
+		logOpts = append(logOpts, logs.WithTail(strconv.Itoa(*opt.Tail)))
+	}
+	if opt.Timestamps {
+		logOpts = append(logOpts, logs.WithTimestamps())
+	}
+
+	linesCh := make(chan compose.LogLine, 1)
+	errCh := make(chan error, 1)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for _, c := range containers {
+		if _, ok := m.services[c.Labels[LabelService]]; !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(c response.ContainerSummary) {
+			defer wg.Done()
+			if err := e.containerLogs(ctx, m, c, logOpts, opt.Timestamps, linesCh); err != nil && ctx.Err() == nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(c)
+	}
+	go func() {
+		defer close(linesCh)
+		defer close(errCh)
+
+		wg.Wait()
+		if err := errors.Join(errs...); err != nil {
+			errCh <- compose.NewComposeLogsError(err)
+		}
+	}()
+	return linesCh, errCh, nil
+}
+
+// containerLogs streams the logs of a single container until the stream ends
+func (e *Engine) containerLogs(ctx context.Context, m *model, c response.ContainerSummary, setters []logs.SetContainerLogsOption, timestamps bool, ch chan compose.LogLine) error {
+	name := containerSummaryName(c)
+	inspect, err := e.api.ContainerInspect(ctx, c.ID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect container %s: %w", name, err)
+	}
+	reader, err := e.api.ContainerLogs(ctx, c.ID, setters...)
+	if err != nil {
+		return fmt.Errorf("failed to read logs of container %s: %w", name, err)
+	}
+	defer reader.Close()
+
+	line := compose.LogLine{
+		Service:   c.Labels[LabelService],
+		Container: strings.TrimPrefix(name, m.project.Name+"-"),
+	}
+	stdout := &lineWriter{ctx: ctx, ch: ch, line: line, stream: compose.LogStreamStdout, timestamps: timestamps}
+	stderr := &lineWriter{ctx: ctx, ch: ch, line: line, stream: compose.LogStreamStderr, timestamps: timestamps}
+	if inspect.Config != nil && inspect.Config.Tty {
+		// a tty merges both streams and is not multiplexed
+		_, err = io.Copy(stdout, reader)
+	} else {
+		_, err = stdcopy.StdCopy(stdout, stderr, reader)
+	}
+	stdout.flush()
+	stderr.flush()
+	if err != nil {
+		return fmt.Errorf("failed to read logs of container %s: %w", name, err)
+	}
+	return nil
+}
+
+// lineWriter sends every complete line written to it as a log line of its container
+type lineWriter struct {
+	ctx        context.Context
+	ch         chan compose.LogLine
+	line       compose.LogLine
+	stream     compose.LogStream
+	timestamps bool
+	buffer     bytes.Buffer
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	n, _ := w.buffer.Write(p)
+	for {
+		i := bytes.IndexByte(w.buffer.Bytes(), '\n')
+		if i < 0 {
+			return n, nil
+		}
+		line := string(w.buffer.Next(i + 1))
+		if err := w.send(strings.TrimRight(line, "\r\n")); err != nil {
+			return n, err
+		}
+	}
+}
+
+// flush sends the remaining incomplete line, if any
+func (w *lineWriter) flush() {
+	if w.buffer.Len() > 0 {
+		_ = w.send(strings.TrimRight(w.buffer.String(), "\r\n"))
+		w.buffer.Reset()
+	}
+}
+
+func (w *lineWriter) send(message string) error {
+	l := w.line
+	l.Stream = w.stream
+	l.Message = message
+	if w.timestamps {
+		if ts, rest, ok := strings.Cut(message, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				l.Timestamp = t
+				l.Message = rest
+			}
+		}
+	}
+	select {
+	case <-w.ctx.Done():
+		return w.ctx.Err()
+	case w.ch <- l:
+		return nil
+	}
+}
@@ -0,0 +1,324 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// defaultNetwork is the network services are attached to when they do not declare any networks
+const defaultNetwork = "default"
+
+// model is the project as seen by a single engine command
+type model struct {
+	project *types.Project
+	// services are the services enabled by the selected profiles
+	services types.Services
+	// order are the names of the enabled services, dependencies first
+	order      []string
+	workingDir string
+}
+
+// model validates the project and resolves the services enabled by profiles.
+// The project is loaded like docker compose loads the file it reads from stdin, see loadProject.
+func (e *Engine) model(ctx context.Context, profiles []string) (*model, error) {
+	if len(e.errs) > 0 {
+		return nil, compose.NewComposeError(errors.Join(e.errs...))
+	}
+	// Marshal validates the project and escapes the values of loaded projects
+	data, err := e.project.Marshal()
+	if err != nil {
+		return nil, compose.NewComposeError(err)
+	}
+	dir := e.project.Unwrap().WorkingDir
+	if dir == "" {
+		// the compose cli uses the current directory as project directory when the file is read from stdin
+		dir, _ = os.Getwd()
+	}
+	project, err := loadProject(ctx, e.project.Unwrap().Name, dir, data)
+	if err != nil {
+		return nil, compose.NewComposeError(err)
+	}
+	services := types.Services{}
+	for name, service := range project.Services {
+		if !service.HasProfile(profiles) {
+			continue
+		}
+		if err := unsupportedFields(project, service); err != nil {
+			return nil, compose.NewComposeError(err)
+		}
+		services[name] = service
+	}
+	order, err := serviceOrder(services)
+	if err != nil {
+		return nil, compose.NewComposeError(err)
+	}
+	return &model{
+		project:    project,
+		services:   services,
+		order:      order,
+		workingDir: dir,
+	}, nil
+}
+
+// loadProject loads the marshaled project like the docker compose cli loads a compose file read from stdin in dir.
+// Variables are interpolated with the os environment and the .env file of dir, $$ is unescaped,
+// relative paths are resolved against dir and env files are merged into the environment of the services.
+func loadProject(ctx context.Context, name, dir string, data []byte) (*types.Project, error) {
+	options, err := cli.NewProjectOptions(nil, cli.WithWorkingDirectory(dir), cli.WithOsEnv, cli.WithEnvFiles(), cli.WithDotEnv)
+	if err != nil {
+		return nil, err
+	}
+	details := types.ConfigDetails{
+		WorkingDir:  dir,
+		ConfigFiles: []types.ConfigFile{{Filename: "-", Content: data}},
+		Environment: options.Environment,
+	}
+	return loader.LoadWithContext(ctx, details, func(o *loader.Options) {
+		o.SetProjectName(name, true)
+		o.ResolvePaths = true
+		// every service is loaded, the enabled services are selected by model
+		o.Profiles = []string{"*"}
+	}, loader.WithDiscardEnvFiles)
+}
+
+// unsupportedFields returns an error naming the first field of the service the engine cannot create its containers with,
+// e.g. secrets that are not read from a file or reserved cpus.
+func unsupportedFields(project *types.Project, service types.ServiceConfig) error {
+	unsupported := func(field string) error {
+		return fmt.Errorf("service %s: %s is not supported by the engine", service.Name, field)
+	}
+	for _, secret := range service.Secrets {
+		if field := fileReferenceField("secrets", types.FileReferenceConfig(secret), types.FileObjectConfig(project.Secrets[secret.Source])); field != "" {
+			return unsupported(field)
+		}
+	}
+	for _, config := range service.Configs {
+		if field := fileReferenceField("configs", types.FileReferenceConfig(config), types.FileObjectConfig(project.Configs[config.Source])); field != "" {
+			return unsupported(field)
+		}
+	}
+	if service.Deploy == nil {
+		return nil
+	}
+	if limits := service.Deploy.Resources.Limits; limits != nil {
+		if len(limits.Devices) > 0 {
+			return unsupported("deploy.resources.limits.devices")
+		}
+		if len(limits.GenericResources) > 0 {
+			return unsupported("deploy.resources.limits.generic_resources")
+		}
+	}
+	if reservations := service.Deploy.Resources.Reservations; reservations != nil {
+		if reservations.NanoCPUs != 0 {
+			return unsupported("deploy.resources.reservations.cpus")
+		}
+		if reservations.Pids != 0 {
+			return unsupported("deploy.resources.reservations.pids")
+		}
+		if len(reservations.GenericResources) > 0 {
+			return unsupported("deploy.resources.reservations.generic_resources")
+		}
+	}
+	return nil
+}
+
+// fileReferenceField returns the field of a secret or config reference that cannot be bind mounted, empty when it can.
+// Only secrets and configs read from a file are mounted, their owner and mode are those of the file.
+func fileReferenceField(kind string, ref types.FileReferenceConfig, object types.FileObjectConfig) string {
+	switch {
+	case bool(object.External):
+		return fmt.Sprintf("%s.%s.external", kind, ref.Source)
+	case object.Environment != "":
+		return fmt.Sprintf("%s.%s.environment", kind, ref.Source)
+	case object.Content != "":
+		return fmt.Sprintf("%s.%s.content", kind, ref.Source)
+	case ref.UID != "":
+		return fmt.Sprintf("%s.%s.uid", kind, ref.Source)
+	case ref.GID != "":
+		return fmt.Sprintf("%s.%s.gid", kind, ref.Source)
+	case ref.Mode != nil:
+		return fmt.Sprintf("%s.%s.mode", kind, ref.Source)
+	}
+	return ""
+}
+
+// serviceDependencies returns the services the service depends on and whether they are required.
+// Like in docker compose network_mode: service:x, volumes_from and links are required implicit dependencies.
+func serviceDependencies(service types.ServiceConfig) map[string]bool {
+	deps := map[string]bool{}
+	for name, dependency := range service.DependsOn {
+		deps[name] = dependency.Required
+	}
+	if target, ok := strings.CutPrefix(service.NetworkMode, "service:"); ok {
+		deps[target] = true
+	}
+	for _, from := range service.VolumesFrom {
+		if strings.HasPrefix(from, "container:") {
+			continue
+		}
+		name, _, _ := strings.Cut(from, ":")
+		deps[name] = true
+	}
+	for _, link := range service.Links {
+		name, _, _ := strings.Cut(link, ":")
+		deps[name] = true
+	}
+	return deps
+}
+
+// serviceOrder sorts the services so every service comes after the services it depends on, see serviceDependencies.
+// Services without a dependency between them are sorted by name.
+func serviceOrder(services types.Services) ([]string, error) {
+	pending := map[string]int{}
+	dependents := map[string][]string{}
+	for name := range services {
+		pending[name] = 0
+	}
+	for name, service := range services {
+		for dep, required := range serviceDependencies(service) {
+			if _, ok := services[dep]; !ok {
+				if required {
+					return nil, fmt.Errorf("service %s depends on %s which is not enabled", name, dep)
+				}
+				continue
+			}
+			pending[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+	ready := []string{}
+	for name, n := range pending {
+		if n == 0 {
+			ready = append(ready, name)
+		}
+	}
+	order := make([]string, 0, len(services))
+	for len(ready) > 0 {
+		sort.Strings(ready)
+		name := ready[0]
+		ready = ready[1:]
+		order = append(order, name)
+		for _, dependent := range dependents[name] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if len(order) != len(services) {
+		cycle := []string{}
+		for name, n := range pending {
+			if n > 0 {
+				cycle = append(cycle, name)
+			}
+		}
+		sort.Strings(cycle)
+		return nil, fmt.Errorf("dependency cycle between services: %v", cycle)
+	}
+	return order, nil
+}
+
+// containerName returns the name of replica number of the service
+func (m *model) containerName(service types.ServiceConfig, number int) string {
+	if service.ContainerName != "" {
+		return service.ContainerName
+	}
+	return fmt.Sprintf("%s-%s-%d", m.project.Name, service.Name, number)
+}
+
+// networkName returns the docker name of the project network key, external networks are not prefixed with the project name
+func (m *model) networkName(key string) string {
+	network, ok := m.project.Networks[key]
+	switch {
+	case ok && network.Name != "":
+		return network.Name
+	case ok && bool(network.External):
+		return key
+	}
+	return fmt.Sprintf("%s_%s", m.project.Name, key)
+}
+
+// volumeName returns the docker name of the project volume key, external volumes are not prefixed with the project name
+func (m *model) volumeName(key string) string {
+	volume, ok := m.project.Volumes[key]
+	switch {
+	case ok && volume.Name != "":
+		return volume.Name
+	case ok && bool(volume.External):
+		return key
+	}
+	return fmt.Sprintf("%s_%s", m.project.Name, key)
+}
+
+// serviceNetworks returns the network keys of the service, sorted by priority
+func serviceNetworks(service types.ServiceConfig) []string {
+	if service.NetworkMode != "" {
+		return nil
+	}
+	if len(service.Networks) == 0 {
+		return []string{defaultNetwork}
+	}
+	return service.NetworksByPriority()
+}
+
+// networks returns the keys of the networks used by the enabled services
+func (m *model) networks() []string {
+	keys := []string{}
+	for _, name := range m.order {
+		for _, key := range serviceNetworks(m.services[name]) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// volumes returns the keys of the project volumes mounted by the enabled services
+func (m *model) volumes() []string {
+	keys := []string{}
+	for _, name := range m.order {
+		for _, mount := range m.services[name].Volumes {
+			if mount.Type != types.VolumeTypeVolume || mount.Source == "" {
+				continue
+			}
+			if _, ok := m.project.Volumes[mount.Source]; ok && !slices.Contains(keys, mount.Source) {
+				keys = append(keys, mount.Source)
+			}
+		}
+	}
+	return keys
+}
+
+// containerNumber returns the replica number of a container from its labels
+func containerNumber(c response.ContainerSummary) int {
+	n, _ := strconv.Atoi(c.Labels[LabelContainerNumber])
+	return n
+}
+
+// byService groups containers by their service label, each group sorted by replica number
+func byService(containers []response.ContainerSummary) map[string][]response.ContainerSummary {
+	groups := map[string][]response.ContainerSummary{}
+	for _, c := range containers {
+		service := c.Labels[LabelService]
+		groups[service] = append(groups[service], c)
+	}
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool {
+			return containerNumber(group[i]) < containerNumber(group[j])
+		})
+	}
+	return groups
+}
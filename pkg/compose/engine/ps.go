@@ -0,0 +1,118 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/client/options/container/list"
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+var (
+	healthStatus = regexp.MustCompile(`\((healthy|unhealthy|health: starting)\)`)
+	exitStatus   = regexp.MustCompile(`^Exited \((-?\d+)\)`)
+)
+
+// PsList returns the containers of the project like (*compose).PsList, sorted by name.
+// Only running containers are listed unless ps.WithAll is used.
+//
+// Supported options are all, filter, status, profiles and service names.
+// ps.WithQuiet and ps.WithServices cannot be used with PsList.
+func (e *Engine) PsList(ctx context.Context, setters ...compose.SetComposePsOption) ([]compose.ServiceContainer, error) {
+	opt := &compose.ComposePsOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, compose.NewComposePsError(err)
+		}
+	}
+	if opt.Quiet {
+		return nil, compose.NewComposePsError(compose.NewComposeFlagError("--quiet", "WithQuiet cannot be used with PsList"))
+	}
+	if opt.Services {
+		return nil, compose.NewComposePsError(compose.NewComposeFlagError("--services", "WithServices cannot be used with PsList"))
+	}
+	m, err := e.model(ctx, opt.Profiles)
+	if err != nil {
+		return nil, compose.NewComposePsError(err)
+	}
+	listOpts := []list.SetContainerListOption{
+		list.WithFilter("label", LabelProject+"="+m.project.Name),
+		list.WithFilter("label", LabelOneoff+"=False"),
+	}
+	if opt.All {
+		listOpts = append(listOpts, list.WithAll())
+	}
+	if opt.Status != "" {
+		listOpts = append(listOpts, list.WithAll(), list.WithFilter("status", opt.Status))
+	}
+	for _, filter := range opt.Filter {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return nil, compose.NewComposePsError(compose.NewComposeFlagError("--filter", fmt.Sprintf("invalid filter %q, expected key=value", filter)))
+		}
+		listOpts = append(listOpts, list.WithFilter(key, value))
+	}
+	containers, err := e.api.ContainerList(ctx, listOpts...)
+	if err != nil {
+		return nil, compose.NewComposePsError(err)
+	}
+	result := make([]compose.ServiceContainer, 0, len(containers))
+	for _, c := range containers {
+		service := c.Labels[LabelService]
+		if len(opt.ServiceNames) > 0 && !slices.Contains(opt.ServiceNames, service) {
+			continue
+		}
+		if _, ok := m.services[service]; !ok && opt.Orphans != nil && !*opt.Orphans {
+			continue
+		}
+		result = append(result, serviceContainer(c))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// serviceContainer converts a container summary into the ps representation of docker compose
+func serviceContainer(c response.ContainerSummary) compose.ServiceContainer {
+	sc := compose.ServiceContainer{
+		ID:         c.ID,
+		Name:       containerSummaryName(c),
+		Image:      c.Image,
+		Command:    c.Command,
+		Project:    c.Labels[LabelProject],
+		Service:    c.Labels[LabelService],
+		State:      string(c.State),
+		Status:     c.Status,
+		CreatedAt:  time.Unix(c.Created, 0).String(),
+		Publishers: []compose.Publisher{},
+	}
+	if match := healthStatus.FindStringSubmatch(c.Status); match != nil {
+		sc.Health = strings.TrimPrefix(match[1], "health: ")
+	}
+	if match := exitStatus.FindStringSubmatch(c.Status); match != nil {
+		sc.ExitCode, _ = strconv.Atoi(match[1])
+	}
+	ports := make([]string, 0, len(c.Ports))
+	for _, port := range c.Ports {
+		sc.Publishers = append(sc.Publishers, compose.Publisher{
+			URL:           port.IP,
+			TargetPort:    int(port.PrivatePort),
+			PublishedPort: int(port.PublicPort),
+			Protocol:      port.Type,
+		})
+		if port.PublicPort == 0 {
+			ports = append(ports, fmt.Sprintf("%d/%s", port.PrivatePort, port.Type))
+			continue
+		}
+		ports = append(ports, fmt.Sprintf("%s:%d->%d/%s", port.IP, port.PublicPort, port.PrivatePort, port.Type))
+	}
+	sc.Ports = strings.Join(ports, ", ")
+	return sc
+}
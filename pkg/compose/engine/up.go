@@ -0,0 +1,495 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/client/options/container/list"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/remove"
+	"github.com/aptd3v/go-contain/pkg/client/options/container/stop"
+	"github.com/aptd3v/go-contain/pkg/client/response"
+	"github.com/aptd3v/go-contain/pkg/compose"
+	"github.com/compose-spec/compose-go/v2/types"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/jsonmessage"
+)
+
+// Up creates the networks, volumes and containers of the project and starts them, like docker compose up --detach.
+// Services are started in depends_on order, waiting for their service_healthy and service_completed_successfully conditions.
+// Containers whose config hash did not change are kept, containers of other services with the project label are orphans.
+//
+// Up requires up.WithDetach, up.WithWait or up.WithNoStart, the engine never attaches to the containers.
+// Supported options are force recreate, no recreate, no start, remove orphans, pull, scale, timeout, wait, wait timeout,
+// profiles and writer, which receives a line per changed resource. The cosmetic options no color, timestamps and menu
+// are accepted and ignored, options the engine does not support return a ComposeFlagError.
+// Images are never built, a service with a build section needs its image to be present.
+func (e *Engine) Up(ctx context.Context, setters ...compose.SetComposeUpOption) error {
+	opt := &compose.ComposeUpOptions{
+		Flags:  []string{"up"},
+		Writer: os.Stdout,
+	}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return compose.NewComposeUpError(err)
+		}
+	}
+	// reuse the flag validation of the cli backend
+	if _, err := opt.GenerateFlags(); err != nil {
+		return compose.NewComposeUpError(err)
+	}
+	if opt.Build {
+		return compose.NewComposeUpError(compose.NewComposeFlagError("--build", "building images is not supported by the engine"))
+	}
+	unsupported := []struct {
+		set    bool
+		flag   string
+		option string
+	}{
+		{opt.NoDeps, "--no-deps", "WithNoDeps"},
+		{opt.AbortOnContainerExit, "--abort-on-container-exit", "WithAbortOnContainerExit"},
+		{opt.AbortOnContainerFailure, "--abort-on-container-failure", "WithAbortOnContainerFailure"},
+		{opt.ExitCodeFrom != nil, "--exit-code-from", "WithExitCodeFrom"},
+		{opt.AlwaysRecreateDeps, "--always-recreate-deps", "WithAlwaysRecreateDeps"},
+		{opt.RenewAnonVolumes, "--renew-anon-volumes", "WithRenewAnonVolumes"},
+		{opt.Watch, "--watch", "WithWatch"},
+		{opt.Attach != nil, "--attach", "WithAttach"},
+		{opt.AttachDependencies, "--attach-dependencies", "WithAttachDependencies"},
+	}
+	for _, u := range unsupported {
+		if u.set {
+			return compose.NewComposeUpError(compose.NewComposeFlagError(u.flag, u.option+" is not supported by the engine"))
+		}
+	}
+	if !opt.Detach && !opt.Wait && !opt.NoStart {
+		return compose.NewComposeUpError(compose.NewComposeFlagError("--detach", "the engine does not attach to containers, use WithDetach, WithWait or WithNoStart"))
+	}
+	m, err := e.model(ctx, opt.Profiles)
+	if err != nil {
+		return compose.NewComposeUpError(err)
+	}
+	if err := e.up(ctx, m, opt); err != nil {
+		return compose.NewComposeUpError(err)
+	}
+	return nil
+}
+
+func (e *Engine) up(ctx context.Context, m *model, opt *compose.ComposeUpOptions) error {
+	for _, key := range m.networks() {
+		if err := e.ensureNetwork(ctx, m, key, opt.Writer); err != nil {
+			return err
+		}
+	}
+	for _, key := range m.volumes() {
+		if err := e.ensureVolume(ctx, m, key, opt.Writer); err != nil {
+			return err
+		}
+	}
+	existing, err := e.projectContainers(ctx, m.project.Name)
+	if err != nil {
+		return err
+	}
+	groups := byService(existing)
+	if err := e.handleOrphans(ctx, m, groups, opt.RemoveOrphans, opt.Timeout, opt.Writer); err != nil {
+		return err
+	}
+	scales := map[string]int{}
+	for _, scale := range opt.Scale {
+		scales[scale.Service] = scale.Num
+	}
+	started := map[string][]string{}
+	for _, name := range m.order {
+		service := m.services[name]
+		if !opt.NoStart {
+			if err := e.waitDependencies(ctx, service, started); err != nil {
+				return err
+			}
+		}
+		if err := e.ensureImage(ctx, service, opt.Pull, opt.Writer); err != nil {
+			return err
+		}
+		scale, ok := scales[name]
+		if !ok {
+			scale = service.GetScale()
+		}
+		ids, err := e.converge(ctx, m, service, scale, groups[name], opt)
+		if err != nil {
+			return err
+		}
+		started[name] = ids
+	}
+	if !opt.Wait || opt.NoStart {
+		return nil
+	}
+	if opt.WaitTimeout != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(*opt.WaitTimeout)*time.Second)
+		defer cancel()
+	}
+	for _, name := range m.order {
+		for _, id := range started[name] {
+			if err := e.waitContainer(ctx, id, isReady); err != nil {
+				return fmt.Errorf("service %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// projectContainers lists all containers of the project, including stopped ones, one-off containers are left out
+func (e *Engine) projectContainers(ctx context.Context, project string) ([]response.ContainerSummary, error) {
+	return e.api.ContainerList(ctx,
+		list.WithAll(),
+		list.WithFilter("label", LabelProject+"="+project),
+		list.WithFilter("label", LabelOneoff+"=False"),
+	)
+}
+
+// handleOrphans removes or reports the containers of services that are not part of the project
+func (e *Engine) handleOrphans(ctx context.Context, m *model, groups map[string][]response.ContainerSummary, removeOrphans bool, timeout *int, w io.Writer) error {
+	orphans := []string{}
+	for service := range groups {
+		if _, ok := m.project.Services[service]; !ok {
+			orphans = append(orphans, service)
+		}
+	}
+	sort.Strings(orphans)
+	for _, service := range orphans {
+		for _, c := range groups[service] {
+			if !removeOrphans {
+				report(w, "Container", containerSummaryName(c), "Orphan")
+				continue
+			}
+			if err := e.removeContainer(ctx, c, timeout, false); err != nil {
+				return err
+			}
+			report(w, "Container", containerSummaryName(c), "Removed")
+		}
+	}
+	return nil
+}
+
+// ensureNetwork creates the project network unless it already exists
+func (e *Engine) ensureNetwork(ctx context.Context, m *model, key string, w io.Writer) error {
+	name := m.networkName(key)
+	config := m.project.Networks[key]
+	if _, err := e.api.NetworkInspect(ctx, name); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	if config.External {
+		return fmt.Errorf("network %s declared as external, but could not be found", name)
+	}
+	labels := map[string]string{}
+	for k, v := range config.Labels {
+		labels[k] = v
+	}
+	labels[LabelProject] = m.project.Name
+	labels[LabelNetwork] = key
+	_, err := e.api.NetworkCreate(ctx, name, func(opt *network.CreateOptions) error {
+		opt.Driver = config.Driver
+		opt.Options = config.DriverOpts
+		opt.Internal = config.Internal
+		opt.Attachable = config.Attachable
+		opt.EnableIPv4 = config.EnableIPv4
+		opt.EnableIPv6 = config.EnableIPv6
+		opt.Labels = labels
+		if config.Ipam.Driver != "" || len(config.Ipam.Config) > 0 {
+			opt.IPAM = &network.IPAM{Driver: config.Ipam.Driver}
+			for _, pool := range config.Ipam.Config {
+				opt.IPAM.Config = append(opt.IPAM.Config, network.IPAMConfig{
+					Subnet:     pool.Subnet,
+					Gateway:    pool.Gateway,
+					IPRange:    pool.IPRange,
+					AuxAddress: pool.AuxiliaryAddresses,
+				})
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create network %s: %w", name, err)
+	}
+	report(w, "Network", name, "Created")
+	return nil
+}
+
+// ensureVolume creates the project volume unless it already exists
+func (e *Engine) ensureVolume(ctx context.Context, m *model, key string, w io.Writer) error {
+	name := m.volumeName(key)
+	config := m.project.Volumes[key]
+	if _, err := e.api.VolumeInspect(ctx, name); err == nil {
+		return nil
+	} else if !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	if config.External {
+		return fmt.Errorf("volume %s declared as external, but could not be found", name)
+	}
+	labels := map[string]string{}
+	for k, v := range config.Labels {
+		labels[k] = v
+	}
+	labels[LabelProject] = m.project.Name
+	labels[LabelVolume] = key
+	_, err := e.api.VolumeCreate(ctx, func(opt *volume.CreateOptions) error {
+		opt.Name = name
+		opt.Driver = config.Driver
+		opt.DriverOpts = config.DriverOpts
+		opt.Labels = labels
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create volume %s: %w", name, err)
+	}
+	report(w, "Volume", name, "Created")
+	return nil
+}
+
+// ensureImage pulls the image of the service according to its pull policy, override is the policy set with up.WithPull
+func (e *Engine) ensureImage(ctx context.Context, service types.ServiceConfig, override *string, w io.Writer) error {
+	policy := service.PullPolicy
+	if override != nil {
+		policy = *override
+	}
+	switch policy {
+	case types.PullPolicyAlways:
+		return e.pullImage(ctx, service, w)
+	case types.PullPolicyBuild:
+		return fmt.Errorf("service %s: pull policy %q is not supported by the engine", service.Name, policy)
+	}
+	_, err := e.api.ImageInspect(ctx, service.Image)
+	if err == nil {
+		return nil
+	}
+	if !cerrdefs.IsNotFound(err) {
+		return err
+	}
+	switch {
+	case policy == types.PullPolicyNever:
+		return fmt.Errorf("service %s: image %s not found and pull policy is never", service.Name, service.Image)
+	case service.Build != nil:
+		return fmt.Errorf("service %s: image %s not found, building images is not supported by the engine", service.Name, service.Image)
+	}
+	return e.pullImage(ctx, service, w)
+}
+
+func (e *Engine) pullImage(ctx context.Context, service types.ServiceConfig, w io.Writer) error {
+	report(w, "Image", service.Image, "Pulling")
+	rc, err := e.api.ImagePull(ctx, service.Image)
+	if err != nil {
+		return fmt.Errorf("service %s: failed to pull %s: %w", service.Name, service.Image, err)
+	}
+	defer rc.Close()
+	// the pull only fails once the stream reports an error
+	if err := jsonmessage.DisplayJSONMessagesStream(rc, io.Discard, 0, false, nil); err != nil {
+		return fmt.Errorf("service %s: failed to pull %s: %w", service.Name, service.Image, err)
+	}
+	report(w, "Image", service.Image, "Pulled")
+	return nil
+}
+
+// converge brings the containers of the service to the desired scale and config, it returns the ids of its containers
+func (e *Engine) converge(ctx context.Context, m *model, service types.ServiceConfig, scale int, existing []response.ContainerSummary, opt *compose.ComposeUpOptions) ([]string, error) {
+	if service.ContainerName != "" && scale > 1 {
+		return nil, fmt.Errorf("service %s: container_name %s cannot be used with scale %d", service.Name, service.ContainerName, scale)
+	}
+	hash, err := configHash(service)
+	if err != nil {
+		return nil, fmt.Errorf("service %s: %w", service.Name, err)
+	}
+	current := map[int]response.ContainerSummary{}
+	for _, c := range existing {
+		n := containerNumber(c)
+		if _, ok := current[n]; ok || n < 1 || n > scale {
+			// scaled down or duplicate replica number
+			if err := e.removeContainer(ctx, c, opt.Timeout, false); err != nil {
+				return nil, err
+			}
+			report(opt.Writer, "Container", containerSummaryName(c), "Removed")
+			continue
+		}
+		current[n] = c
+	}
+	ids := make([]string, 0, scale)
+	for n := 1; n <= scale; n++ {
+		c, ok := current[n]
+		if ok {
+			recreate := opt.ForceRecreate || (!opt.NoRecreate && c.Labels[LabelConfigHash] != hash)
+			if !recreate {
+				if c.State != container.StateRunning && !opt.NoStart {
+					if err := e.api.ContainerStart(ctx, c.ID); err != nil {
+						return nil, fmt.Errorf("failed to start container %s: %w", containerSummaryName(c), err)
+					}
+					report(opt.Writer, "Container", containerSummaryName(c), "Started")
+				}
+				ids = append(ids, c.ID)
+				continue
+			}
+			if err := e.removeContainer(ctx, c, opt.Timeout, false); err != nil {
+				return nil, err
+			}
+			report(opt.Writer, "Container", containerSummaryName(c), "Recreate")
+		}
+		created, err := m.newContainer(service, n, hash)
+		if err != nil {
+			return nil, err
+		}
+		res, err := e.api.ContainerCreate(ctx, created)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create container %s: %w", created.Name, err)
+		}
+		report(opt.Writer, "Container", created.Name, "Created")
+		if !opt.NoStart {
+			if err := e.api.ContainerStart(ctx, res.ID); err != nil {
+				return nil, fmt.Errorf("failed to start container %s: %w", created.Name, err)
+			}
+			report(opt.Writer, "Container", created.Name, "Started")
+		}
+		ids = append(ids, res.ID)
+	}
+	return ids, nil
+}
+
+// removeContainer stops and removes a container, volumes also removes its anonymous volumes
+func (e *Engine) removeContainer(ctx context.Context, c response.ContainerSummary, timeout *int, volumes bool) error {
+	if c.State == container.StateRunning || c.State == container.StatePaused || c.State == container.StateRestarting {
+		stopOpts := []stop.SetContainerStopOption{}
+		if timeout != nil {
+			stopOpts = append(stopOpts, stop.WithTimeout(*timeout))
+		}
+		if err := e.api.ContainerStop(ctx, c.ID, stopOpts...); err != nil && !cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("failed to stop container %s: %w", containerSummaryName(c), err)
+		}
+	}
+	removeOpts := []remove.SetContainerRemoveOption{remove.WithForce()}
+	if volumes {
+		removeOpts = append(removeOpts, remove.WithVolumes())
+	}
+	if err := e.api.ContainerRemove(ctx, c.ID, removeOpts...); err != nil && !cerrdefs.IsNotFound(err) {
+		return fmt.Errorf("failed to remove container %s: %w", containerSummaryName(c), err)
+	}
+	return nil
+}
+
+// waitDependencies blocks until the depends_on conditions of the service are met
+func (e *Engine) waitDependencies(ctx context.Context, service types.ServiceConfig, started map[string][]string) error {
+	deps := make([]string, 0, len(service.DependsOn))
+	for name := range service.DependsOn {
+		deps = append(deps, name)
+	}
+	sort.Strings(deps)
+	for _, name := range deps {
+		var done func(*response.ContainerInspect) (bool, error)
+		switch service.DependsOn[name].Condition {
+		case types.ServiceConditionHealthy:
+			done = isHealthy
+		case types.ServiceConditionCompletedSuccessfully:
+			done = isCompleted
+		default:
+			continue
+		}
+		for _, id := range started[name] {
+			if err := e.waitContainer(ctx, id, done); err != nil {
+				return fmt.Errorf("service %s depends on %s: %w", service.Name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// waitContainer polls the container until done reports true, done returns an error when the condition cannot be met anymore
+func (e *Engine) waitContainer(ctx context.Context, id string, done func(*response.ContainerInspect) (bool, error)) error {
+	ticker := time.NewTicker(e.pollInterval)
+	defer ticker.Stop()
+	for {
+		inspect, err := e.api.ContainerInspect(ctx, id)
+		if err != nil {
+			return err
+		}
+		ok, err := done(inspect)
+		if err != nil {
+			return fmt.Errorf("container %s %w", strings.TrimPrefix(inspect.Name, "/"), err)
+		}
+		if ok {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// isHealthy is the service_healthy condition
+func isHealthy(c *response.ContainerInspect) (bool, error) {
+	if c.State == nil {
+		return false, nil
+	}
+	if c.State.Health == nil {
+		return false, fmt.Errorf("has no healthcheck")
+	}
+	switch c.State.Health.Status {
+	case container.Healthy:
+		return true, nil
+	case container.Unhealthy:
+		return false, fmt.Errorf("is unhealthy")
+	}
+	if c.State.Status == container.StateExited || c.State.Status == container.StateDead {
+		return false, fmt.Errorf("exited with code %d", c.State.ExitCode)
+	}
+	return false, nil
+}
+
+// isCompleted is the service_completed_successfully condition
+func isCompleted(c *response.ContainerInspect) (bool, error) {
+	if c.State == nil || (c.State.Status != container.StateExited && c.State.Status != container.StateDead) {
+		return false, nil
+	}
+	if c.State.ExitCode != 0 {
+		return false, fmt.Errorf("exited with code %d", c.State.ExitCode)
+	}
+	return true, nil
+}
+
+// isReady is the up.WithWait condition, containers with a healthcheck must be healthy, others running
+func isReady(c *response.ContainerInspect) (bool, error) {
+	if c.State != nil && c.State.Health != nil {
+		return isHealthy(c)
+	}
+	if c.State == nil {
+		return false, nil
+	}
+	switch c.State.Status {
+	case container.StateRunning:
+		return true, nil
+	case container.StateExited, container.StateDead:
+		return false, fmt.Errorf("exited with code %d", c.State.ExitCode)
+	}
+	return false, nil
+}
+
+// containerSummaryName returns the name of the container without the leading slash
+func containerSummaryName(c response.ContainerSummary) string {
+	if len(c.Names) == 0 {
+		return c.ID
+	}
+	return strings.TrimPrefix(c.Names[0], "/")
+}
+
+// report writes a progress line like the plain progress output of docker compose
+func report(w io.Writer, kind, name, status string) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, "%s %s  %s\n", kind, name, status)
+}
@@ -1,8 +1,10 @@
 package compose
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"regexp"
 )
 
 // Errors for the compose command and its setters.
@@ -21,8 +23,31 @@ var (
 	ErrComposeBuildError  = fmt.Errorf("compose build error")
 	ErrComposePullError   = fmt.Errorf("compose pull error")
 	ErrComposeExecError   = fmt.Errorf("compose exec error")
+	ErrComposeRunError    = fmt.Errorf("compose run error")
+	ErrComposeCreateError = fmt.Errorf("compose create error")
+	ErrComposeRmError     = fmt.Errorf("compose rm error")
+	ErrComposePauseError  = fmt.Errorf("compose pause error")
+	ErrComposeUnpauseError = fmt.Errorf("compose unpause error")
+	ErrComposeWaitError   = fmt.Errorf("compose wait error")
+	ErrComposeCpError     = fmt.Errorf("compose cp error")
+	ErrComposeTopError    = fmt.Errorf("compose top error")
+	ErrComposePortError   = fmt.Errorf("compose port error")
+	ErrComposeConfigError = fmt.Errorf("compose config error")
+	ErrComposeLsError     = fmt.Errorf("compose ls error")
+	ErrComposeImagesError = fmt.Errorf("compose images error")
+	ErrComposeStatsError  = fmt.Errorf("compose stats error")
+	ErrComposeAttachError = fmt.Errorf("compose attach error")
+	ErrComposePushError   = fmt.Errorf("compose push error")
+	ErrComposeScaleError  = fmt.Errorf("compose scale error")
+	ErrComposePublishError = fmt.Errorf("compose publish error")
+	ErrComposeWatchError  = fmt.Errorf("compose watch error")
 )
 
+// exitCoder is implemented by errors that carry a process exit code, such as *exec.ExitError
+type exitCoder interface {
+	ExitCode() int
+}
+
 // ComposeFlagError is the error for the compose flag
 type ComposeFlagError struct {
 	Flag    string
@@ -53,10 +78,11 @@ func IsComposeFlagError(err error) bool {
 // ComposeKillError is the error for the compose kill command
 type ComposeKillError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeKillError) Unwrap() error {
-	return ErrComposeKillError
+func (e *ComposeKillError) Unwrap() []error {
+	return unwrapCause(ErrComposeKillError, e.Err)
 }
 
 func (e *ComposeKillError) Error() string {
@@ -67,6 +93,7 @@ func (e *ComposeKillError) Error() string {
 func NewComposeKillError(err error) *ComposeKillError {
 	return &ComposeKillError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -78,10 +105,11 @@ func IsComposeKillError(err error) bool {
 // ComposeEventsError is the error for the compose events command
 type ComposeEventsError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeEventsError) Unwrap() error {
-	return ErrComposeEventsError
+func (e *ComposeEventsError) Unwrap() []error {
+	return unwrapCause(ErrComposeEventsError, e.Err)
 }
 
 func (e *ComposeEventsError) Error() string {
@@ -92,6 +120,7 @@ func (e *ComposeEventsError) Error() string {
 func NewComposeEventsError(err error) *ComposeEventsError {
 	return &ComposeEventsError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -103,10 +132,11 @@ func IsComposeEventsError(err error) bool {
 // ComposeError is the error for the compose command
 type ComposeError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeError) Unwrap() error {
-	return ErrComposeError
+func (e *ComposeError) Unwrap() []error {
+	return unwrapCause(ErrComposeError, e.Err)
 }
 func (e *ComposeError) Error() string {
 	return fmt.Sprintf("compose exec error: %s", e.Message)
@@ -115,6 +145,7 @@ func (e *ComposeError) Error() string {
 func NewComposeError(err error) *ComposeError {
 	return &ComposeError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -126,10 +157,11 @@ func IsComposeError(err error) bool {
 // ComposeUpError is the error for the compose up command
 type ComposeUpError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeUpError) Unwrap() error {
-	return ErrComposeUpError
+func (e *ComposeUpError) Unwrap() []error {
+	return unwrapCause(ErrComposeUpError, e.Err)
 }
 
 func (e *ComposeUpError) Error() string {
@@ -140,6 +172,7 @@ func (e *ComposeUpError) Error() string {
 func NewComposeUpError(err error) *ComposeUpError {
 	return &ComposeUpError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -151,10 +184,11 @@ func IsComposeUpError(err error) bool {
 // ComposeDownError is the error for the compose down command
 type ComposeDownError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeDownError) Unwrap() error {
-	return ErrComposeDownError
+func (e *ComposeDownError) Unwrap() []error {
+	return unwrapCause(ErrComposeDownError, e.Err)
 }
 
 func (e *ComposeDownError) Error() string {
@@ -165,6 +199,7 @@ func (e *ComposeDownError) Error() string {
 func NewComposeDownError(err error) *ComposeDownError {
 	return &ComposeDownError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -176,10 +211,11 @@ func IsComposeDownError(err error) bool {
 // ComposeLogsError is the error for the compose logs command
 type ComposeLogsError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeLogsError) Unwrap() error {
-	return ErrComposeLogsError
+func (e *ComposeLogsError) Unwrap() []error {
+	return unwrapCause(ErrComposeLogsError, e.Err)
 }
 
 func (e *ComposeLogsError) Error() string {
@@ -190,6 +226,7 @@ func (e *ComposeLogsError) Error() string {
 func NewComposeLogsError(err error) *ComposeLogsError {
 	return &ComposeLogsError{
 		Message: err.Error(),
+		Err:     err,
 	}
 }
 
@@ -201,90 +238,553 @@ func IsComposeLogsError(err error) bool {
 // ComposePsError is the error for the compose ps command
 type ComposePsError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposePsError) Unwrap() error { return ErrComposePsError }
+func (e *ComposePsError) Unwrap() []error { return unwrapCause(ErrComposePsError, e.Err) }
 func (e *ComposePsError) Error() string { return fmt.Sprintf("compose ps error: %s", e.Message) }
 
 func NewComposePsError(err error) *ComposePsError {
-	return &ComposePsError{Message: err.Error()}
+	return &ComposePsError{Message: err.Error(), Err: err}
 }
 func IsComposePsError(err error) bool { return errors.Is(err, ErrComposePsError) }
 
 // ComposeStartError is the error for the compose start command
 type ComposeStartError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeStartError) Unwrap() error { return ErrComposeStartError }
+func (e *ComposeStartError) Unwrap() []error { return unwrapCause(ErrComposeStartError, e.Err) }
 func (e *ComposeStartError) Error() string { return fmt.Sprintf("compose start error: %s", e.Message) }
 
 func NewComposeStartError(err error) *ComposeStartError {
-	return &ComposeStartError{Message: err.Error()}
+	return &ComposeStartError{Message: err.Error(), Err: err}
 }
 func IsComposeStartError(err error) bool { return errors.Is(err, ErrComposeStartError) }
 
 // ComposeStopError is the error for the compose stop command
 type ComposeStopError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeStopError) Unwrap() error { return ErrComposeStopError }
+func (e *ComposeStopError) Unwrap() []error { return unwrapCause(ErrComposeStopError, e.Err) }
 func (e *ComposeStopError) Error() string { return fmt.Sprintf("compose stop error: %s", e.Message) }
 
 func NewComposeStopError(err error) *ComposeStopError {
-	return &ComposeStopError{Message: err.Error()}
+	return &ComposeStopError{Message: err.Error(), Err: err}
 }
 func IsComposeStopError(err error) bool { return errors.Is(err, ErrComposeStopError) }
 
 // ComposeRestartError is the error for the compose restart command
 type ComposeRestartError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeRestartError) Unwrap() error { return ErrComposeRestartError }
+func (e *ComposeRestartError) Unwrap() []error { return unwrapCause(ErrComposeRestartError, e.Err) }
 func (e *ComposeRestartError) Error() string { return fmt.Sprintf("compose restart error: %s", e.Message) }
 
 func NewComposeRestartError(err error) *ComposeRestartError {
-	return &ComposeRestartError{Message: err.Error()}
+	return &ComposeRestartError{Message: err.Error(), Err: err}
 }
 func IsComposeRestartError(err error) bool { return errors.Is(err, ErrComposeRestartError) }
 
 // ComposeBuildError is the error for the compose build command
 type ComposeBuildError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeBuildError) Unwrap() error { return ErrComposeBuildError }
+func (e *ComposeBuildError) Unwrap() []error { return unwrapCause(ErrComposeBuildError, e.Err) }
 func (e *ComposeBuildError) Error() string { return fmt.Sprintf("compose build error: %s", e.Message) }
 
 func NewComposeBuildError(err error) *ComposeBuildError {
-	return &ComposeBuildError{Message: err.Error()}
+	return &ComposeBuildError{Message: err.Error(), Err: err}
 }
 func IsComposeBuildError(err error) bool { return errors.Is(err, ErrComposeBuildError) }
 
 // ComposePullError is the error for the compose pull command
 type ComposePullError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposePullError) Unwrap() error { return ErrComposePullError }
+func (e *ComposePullError) Unwrap() []error { return unwrapCause(ErrComposePullError, e.Err) }
 func (e *ComposePullError) Error() string { return fmt.Sprintf("compose pull error: %s", e.Message) }
 
 func NewComposePullError(err error) *ComposePullError {
-	return &ComposePullError{Message: err.Error()}
+	return &ComposePullError{Message: err.Error(), Err: err}
 }
 func IsComposePullError(err error) bool { return errors.Is(err, ErrComposePullError) }
 
 // ComposeExecError is the error for the compose exec command
 type ComposeExecError struct {
 	Message string
+	Err     error
 }
 
-func (e *ComposeExecError) Unwrap() error { return ErrComposeExecError }
+func (e *ComposeExecError) Unwrap() []error { return unwrapCause(ErrComposeExecError, e.Err) }
 func (e *ComposeExecError) Error() string { return fmt.Sprintf("compose exec error: %s", e.Message) }
 
 func NewComposeExecError(err error) *ComposeExecError {
-	return &ComposeExecError{Message: err.Error()}
+	return &ComposeExecError{Message: err.Error(), Err: err}
 }
 func IsComposeExecError(err error) bool { return errors.Is(err, ErrComposeExecError) }
+
+// ComposeRunError is the error for the compose run command.
+// ExitCode holds the exit code of the compose process, which is the exit code of the
+// one-off container when it ran to completion. It is -1 when the process did not exit normally.
+type ComposeRunError struct {
+	Message  string
+	ExitCode int
+	Err      error
+}
+
+func (e *ComposeRunError) Unwrap() []error { return unwrapCause(ErrComposeRunError, e.Err) }
+func (e *ComposeRunError) Error() string { return fmt.Sprintf("compose run error: %s", e.Message) }
+
+// NewComposeRunError creates a new ComposeRunError from the given error,
+// extracting the exit code when err is an *exec.ExitError or another error with an ExitCode method.
+func NewComposeRunError(err error) *ComposeRunError {
+	exitCode := -1
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &ComposeRunError{Message: err.Error(), Err: err, ExitCode: exitCode}
+}
+func IsComposeRunError(err error) bool { return errors.Is(err, ErrComposeRunError) }
+
+// ComposeCreateError is the error for the compose create command
+type ComposeCreateError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeCreateError) Unwrap() []error { return unwrapCause(ErrComposeCreateError, e.Err) }
+func (e *ComposeCreateError) Error() string { return fmt.Sprintf("compose create error: %s", e.Message) }
+
+func NewComposeCreateError(err error) *ComposeCreateError {
+	return &ComposeCreateError{Message: err.Error(), Err: err}
+}
+func IsComposeCreateError(err error) bool { return errors.Is(err, ErrComposeCreateError) }
+
+// ComposeRmError is the error for the compose rm command
+type ComposeRmError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeRmError) Unwrap() []error { return unwrapCause(ErrComposeRmError, e.Err) }
+func (e *ComposeRmError) Error() string { return fmt.Sprintf("compose rm error: %s", e.Message) }
+
+func NewComposeRmError(err error) *ComposeRmError {
+	return &ComposeRmError{Message: err.Error(), Err: err}
+}
+func IsComposeRmError(err error) bool { return errors.Is(err, ErrComposeRmError) }
+
+// ComposePauseError is the error for the compose pause command
+type ComposePauseError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposePauseError) Unwrap() []error { return unwrapCause(ErrComposePauseError, e.Err) }
+func (e *ComposePauseError) Error() string { return fmt.Sprintf("compose pause error: %s", e.Message) }
+
+func NewComposePauseError(err error) *ComposePauseError {
+	return &ComposePauseError{Message: err.Error(), Err: err}
+}
+func IsComposePauseError(err error) bool { return errors.Is(err, ErrComposePauseError) }
+
+// ComposeUnpauseError is the error for the compose unpause command
+type ComposeUnpauseError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeUnpauseError) Unwrap() []error { return unwrapCause(ErrComposeUnpauseError, e.Err) }
+func (e *ComposeUnpauseError) Error() string {
+	return fmt.Sprintf("compose unpause error: %s", e.Message)
+}
+
+func NewComposeUnpauseError(err error) *ComposeUnpauseError {
+	return &ComposeUnpauseError{Message: err.Error(), Err: err}
+}
+func IsComposeUnpauseError(err error) bool { return errors.Is(err, ErrComposeUnpauseError) }
+
+// ComposeWaitError is the error for the compose wait command.
+// ExitCode holds the exit code of the first container that stopped, or -1 when the
+// compose process did not exit normally.
+type ComposeWaitError struct {
+	Message  string
+	ExitCode int
+	Err      error
+}
+
+func (e *ComposeWaitError) Unwrap() []error { return unwrapCause(ErrComposeWaitError, e.Err) }
+func (e *ComposeWaitError) Error() string { return fmt.Sprintf("compose wait error: %s", e.Message) }
+
+// NewComposeWaitError creates a new ComposeWaitError from the given error,
+// extracting the exit code when err is an *exec.ExitError or another error with an ExitCode method.
+func NewComposeWaitError(err error) *ComposeWaitError {
+	exitCode := -1
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &ComposeWaitError{Message: err.Error(), Err: err, ExitCode: exitCode}
+}
+func IsComposeWaitError(err error) bool { return errors.Is(err, ErrComposeWaitError) }
+
+// ComposeCpError is the error for the compose cp command
+type ComposeCpError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeCpError) Unwrap() []error { return unwrapCause(ErrComposeCpError, e.Err) }
+func (e *ComposeCpError) Error() string { return fmt.Sprintf("compose cp error: %s", e.Message) }
+
+func NewComposeCpError(err error) *ComposeCpError {
+	return &ComposeCpError{Message: err.Error(), Err: err}
+}
+func IsComposeCpError(err error) bool { return errors.Is(err, ErrComposeCpError) }
+
+// ComposeTopError is the error for the compose top command
+type ComposeTopError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeTopError) Unwrap() []error { return unwrapCause(ErrComposeTopError, e.Err) }
+func (e *ComposeTopError) Error() string { return fmt.Sprintf("compose top error: %s", e.Message) }
+
+func NewComposeTopError(err error) *ComposeTopError {
+	return &ComposeTopError{Message: err.Error(), Err: err}
+}
+func IsComposeTopError(err error) bool { return errors.Is(err, ErrComposeTopError) }
+
+// ComposePortError is the error for the compose port command
+type ComposePortError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposePortError) Unwrap() []error { return unwrapCause(ErrComposePortError, e.Err) }
+func (e *ComposePortError) Error() string { return fmt.Sprintf("compose port error: %s", e.Message) }
+
+func NewComposePortError(err error) *ComposePortError {
+	return &ComposePortError{Message: err.Error(), Err: err}
+}
+func IsComposePortError(err error) bool { return errors.Is(err, ErrComposePortError) }
+
+// ComposeConfigError is the error for the compose config command
+type ComposeConfigError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeConfigError) Unwrap() []error { return unwrapCause(ErrComposeConfigError, e.Err) }
+func (e *ComposeConfigError) Error() string {
+	return fmt.Sprintf("compose config error: %s", e.Message)
+}
+
+func NewComposeConfigError(err error) *ComposeConfigError {
+	return &ComposeConfigError{Message: err.Error(), Err: err}
+}
+func IsComposeConfigError(err error) bool { return errors.Is(err, ErrComposeConfigError) }
+
+// ComposeLsError is the error for the compose ls command
+type ComposeLsError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeLsError) Unwrap() []error { return unwrapCause(ErrComposeLsError, e.Err) }
+func (e *ComposeLsError) Error() string { return fmt.Sprintf("compose ls error: %s", e.Message) }
+
+func NewComposeLsError(err error) *ComposeLsError {
+	return &ComposeLsError{Message: err.Error(), Err: err}
+}
+func IsComposeLsError(err error) bool { return errors.Is(err, ErrComposeLsError) }
+
+// ComposeImagesError is the error for the compose images command
+type ComposeImagesError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeImagesError) Unwrap() []error { return unwrapCause(ErrComposeImagesError, e.Err) }
+func (e *ComposeImagesError) Error() string {
+	return fmt.Sprintf("compose images error: %s", e.Message)
+}
+
+func NewComposeImagesError(err error) *ComposeImagesError {
+	return &ComposeImagesError{Message: err.Error(), Err: err}
+}
+func IsComposeImagesError(err error) bool { return errors.Is(err, ErrComposeImagesError) }
+
+// ComposeStatsError is the error for the compose stats command
+type ComposeStatsError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeStatsError) Unwrap() []error { return unwrapCause(ErrComposeStatsError, e.Err) }
+func (e *ComposeStatsError) Error() string { return fmt.Sprintf("compose stats error: %s", e.Message) }
+
+func NewComposeStatsError(err error) *ComposeStatsError {
+	return &ComposeStatsError{Message: err.Error(), Err: err}
+}
+func IsComposeStatsError(err error) bool { return errors.Is(err, ErrComposeStatsError) }
+
+// ComposeAttachError is the error for the compose attach command
+type ComposeAttachError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeAttachError) Unwrap() []error { return unwrapCause(ErrComposeAttachError, e.Err) }
+func (e *ComposeAttachError) Error() string {
+	return fmt.Sprintf("compose attach error: %s", e.Message)
+}
+
+func NewComposeAttachError(err error) *ComposeAttachError {
+	return &ComposeAttachError{Message: err.Error(), Err: err}
+}
+func IsComposeAttachError(err error) bool { return errors.Is(err, ErrComposeAttachError) }
+
+// ComposePushError is the error for the compose push command
+type ComposePushError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposePushError) Unwrap() []error { return unwrapCause(ErrComposePushError, e.Err) }
+func (e *ComposePushError) Error() string {
+	return fmt.Sprintf("compose push error: %s", e.Message)
+}
+
+func NewComposePushError(err error) *ComposePushError {
+	return &ComposePushError{Message: err.Error(), Err: err}
+}
+func IsComposePushError(err error) bool { return errors.Is(err, ErrComposePushError) }
+
+// ComposeScaleError is the error for the compose scale command
+type ComposeScaleError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeScaleError) Unwrap() []error { return unwrapCause(ErrComposeScaleError, e.Err) }
+func (e *ComposeScaleError) Error() string {
+	return fmt.Sprintf("compose scale error: %s", e.Message)
+}
+
+func NewComposeScaleError(err error) *ComposeScaleError {
+	return &ComposeScaleError{Message: err.Error(), Err: err}
+}
+func IsComposeScaleError(err error) bool { return errors.Is(err, ErrComposeScaleError) }
+
+// ComposePublishError is the error for the compose publish command
+type ComposePublishError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposePublishError) Unwrap() []error { return unwrapCause(ErrComposePublishError, e.Err) }
+func (e *ComposePublishError) Error() string {
+	return fmt.Sprintf("compose publish error: %s", e.Message)
+}
+
+func NewComposePublishError(err error) *ComposePublishError {
+	return &ComposePublishError{Message: err.Error(), Err: err}
+}
+func IsComposePublishError(err error) bool { return errors.Is(err, ErrComposePublishError) }
+
+// ComposeWatchError is the error for the compose watch command
+type ComposeWatchError struct {
+	Message string
+	Err     error
+}
+
+func (e *ComposeWatchError) Unwrap() []error { return unwrapCause(ErrComposeWatchError, e.Err) }
+func (e *ComposeWatchError) Error() string { return fmt.Sprintf("compose watch error: %s", e.Message) }
+
+func NewComposeWatchError(err error) *ComposeWatchError {
+	return &ComposeWatchError{Message: err.Error(), Err: err}
+}
+func IsComposeWatchError(err error) bool { return errors.Is(err, ErrComposeWatchError) }
+
+// unwrapCause returns the sentinel of a compose error followed by its cause, if any
+func unwrapCause(sentinel, cause error) []error {
+	if cause == nil {
+		return []error{sentinel}
+	}
+	return []error{sentinel, cause}
+}
+
+// Classified causes of a failed compose command, derived from its stderr output.
+// They are matched with errors.Is on the error returned by any compose command.
+var (
+	ErrPortAlreadyAllocated = fmt.Errorf("port is already allocated")
+	ErrImageNotFound        = fmt.Errorf("image not found")
+	ErrPullAccessDenied     = fmt.Errorf("pull access denied")
+	ErrNetworkNotFound      = fmt.Errorf("network not found")
+	ErrDaemonUnreachable    = fmt.Errorf("docker daemon unreachable")
+	ErrInvalidComposeFile   = fmt.Errorf("invalid compose file")
+)
+
+// stderrClassifiers map stderr output to a classified cause, the first match wins
+var stderrClassifiers = []struct {
+	re  *regexp.Regexp
+	err error
+}{
+	{regexp.MustCompile(`(?i)cannot connect to the docker daemon|error during connect|is the docker daemon running`), ErrDaemonUnreachable},
+	{regexp.MustCompile(`(?i)invalid compose project|^validating .+:|yaml: (line \d+|unmarshal errors)|additional propert(y|ies) .* not allowed|services\.\S+ must be a`), ErrInvalidComposeFile},
+	{regexp.MustCompile(`(?i)port is already allocated|address already in use`), ErrPortAlreadyAllocated},
+	{regexp.MustCompile(`(?i)pull access denied|requested access to the resource is denied|unauthorized: authentication required`), ErrPullAccessDenied},
+	{regexp.MustCompile(`(?i)manifest unknown|manifest for \S+ not found|no such image|not found: manifest|failed to resolve reference .*: not found`), ErrImageNotFound},
+	{regexp.MustCompile(`(?i)network \S+ (declared as external, but could )?not( be)? found|no such network`), ErrNetworkNotFound},
+}
+
+// classifyStderr returns the classified cause of the stderr output, or nil when it is not recognized
+func classifyStderr(stderr string) error {
+	for _, line := range splitLines([]byte(stderr)) {
+		for _, classifier := range stderrClassifiers {
+			if classifier.re.MatchString(line) {
+				return classifier.err
+			}
+		}
+	}
+	return nil
+}
+
+// CommandError is the error of a compose process that did not exit successfully.
+// It is wrapped by the error of the command that ran it, use errors.As to access it.
+type CommandError struct {
+	// Args is the argv of the process
+	Args []string
+	// ExitCode is the exit code of the process, -1 when it did not exit normally
+	ExitCode int
+	// Stderr is the captured stderr output, truncated to its last 64KiB.
+	// --progress json output is rendered as plain text.
+	Stderr string
+	// Cause is the classified cause such as ErrPortAlreadyAllocated, nil when unknown
+	Cause error
+	Err   error
+}
+
+func (e *CommandError) Unwrap() []error {
+	if e.Cause == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Err, e.Cause}
+}
+
+func (e *CommandError) Error() string {
+	if reason := lastLine(e.Stderr); reason != "" {
+		return fmt.Sprintf("%s: %s", e.Err, reason)
+	}
+	return e.Err.Error()
+}
+
+// NewCommandError creates a new CommandError for the failed command with its captured stderr output
+func NewCommandError(cmd *Command, err error, stderr string) *CommandError {
+	exitCode := -1
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &CommandError{
+		Args:     cmd.Argv(),
+		ExitCode: exitCode,
+		Stderr:   stderr,
+		Cause:    classifyStderr(stderr),
+		Err:      err,
+	}
+}
+
+// IsCommandError checks if the error is or wraps a CommandError.
+func IsCommandError(err error) bool {
+	var cmdErr *CommandError
+	return errors.As(err, &cmdErr)
+}
+
+// ExitCode returns the exit code of the compose process that caused err.
+// ok is false when err was not caused by a process exiting with a code.
+func ExitCode(err error) (code int, ok bool) {
+	var exitErr exitCoder
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), true
+	}
+	return 0, false
+}
+
+// lastLine returns the last non-empty line of s without ansi escape codes
+func lastLine(s string) string {
+	lines := splitLines([]byte(ansiEscape.ReplaceAllString(s, "")))
+	if len(lines) == 0 {
+		return ""
+	}
+	return lines[len(lines)-1]
+}
+
+// Errors for compose commands interrupted by their context, see WithContextErrors.
+var (
+	ErrComposeCanceledError         = fmt.Errorf("compose canceled error")
+	ErrComposeDeadlineExceededError = fmt.Errorf("compose deadline exceeded error")
+)
+
+// ComposeCanceledError is the error for a compose command stopped because its context was canceled
+type ComposeCanceledError struct {
+	// Command is the interrupted compose subcommand, e.g. "up"
+	Command string
+	Err     error
+}
+
+func (e *ComposeCanceledError) Unwrap() []error {
+	return []error{ErrComposeCanceledError, context.Canceled, e.Err}
+}
+
+func (e *ComposeCanceledError) Error() string {
+	return fmt.Sprintf("compose %s canceled: %s", e.Command, e.Err)
+}
+
+// NewComposeCanceledError creates a new ComposeCanceledError for the interrupted command.
+func NewComposeCanceledError(command string, err error) *ComposeCanceledError {
+	return &ComposeCanceledError{Command: command, Err: err}
+}
+
+// IsComposeCanceledError checks if the error is a ComposeCanceledError.
+func IsComposeCanceledError(err error) bool {
+	return errors.Is(err, ErrComposeCanceledError)
+}
+
+// ComposeDeadlineExceededError is the error for a compose command stopped because the deadline of its context exceeded
+type ComposeDeadlineExceededError struct {
+	// Command is the interrupted compose subcommand, e.g. "up"
+	Command string
+	Err     error
+}
+
+func (e *ComposeDeadlineExceededError) Unwrap() []error {
+	return []error{ErrComposeDeadlineExceededError, context.DeadlineExceeded, e.Err}
+}
+
+func (e *ComposeDeadlineExceededError) Error() string {
+	return fmt.Sprintf("compose %s deadline exceeded: %s", e.Command, e.Err)
+}
+
+// NewComposeDeadlineExceededError creates a new ComposeDeadlineExceededError for the interrupted command.
+func NewComposeDeadlineExceededError(command string, err error) *ComposeDeadlineExceededError {
+	return &ComposeDeadlineExceededError{Command: command, Err: err}
+}
+
+// IsComposeDeadlineExceededError checks if the error is a ComposeDeadlineExceededError.
+func IsComposeDeadlineExceededError(err error) bool {
+	return errors.Is(err, ErrComposeDeadlineExceededError)
+}
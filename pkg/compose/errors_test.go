@@ -0,0 +1,124 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewComposeRunError(t *testing.T) {
+	err := NewComposeRunError(exec.Command("sh", "-c", "exit 3").Run())
+	assert.True(t, IsComposeRunError(err))
+	assert.Equal(t, 3, err.ExitCode)
+
+	err = NewComposeRunError(errors.New("service is required"))
+	assert.True(t, IsComposeRunError(err))
+	assert.Equal(t, -1, err.ExitCode)
+}
+
+func TestClassifyStderr(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		expected error
+	}{
+		{fixture: "port_allocated.txt", expected: ErrPortAlreadyAllocated},
+		{fixture: "image_not_found.txt", expected: ErrImageNotFound},
+		{fixture: "pull_access_denied.txt", expected: ErrPullAccessDenied},
+		{fixture: "network_not_found.txt", expected: ErrNetworkNotFound},
+		{fixture: "daemon_unreachable.txt", expected: ErrDaemonUnreachable},
+		{fixture: "invalid_compose_file.txt", expected: ErrInvalidComposeFile},
+		{fixture: "invalid_compose_project.txt", expected: ErrInvalidComposeFile},
+		{fixture: "unknown.txt", expected: nil},
+		{fixture: "port_allocated.json", expected: ErrPortAlreadyAllocated},
+		{fixture: "invalid_compose_file.json", expected: ErrInvalidComposeFile},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(filepath.Join("testdata", "stderr", tt.fixture))
+		if !assert.NoError(t, err, tt.fixture) {
+			continue
+		}
+		stderr := string(data)
+		if filepath.Ext(tt.fixture) == ".json" {
+			// --progress json output, see CommandError.Stderr
+			stderr = plainProgress(stderr)
+		}
+		assert.Equal(t, tt.expected, classifyStderr(stderr), tt.fixture)
+	}
+}
+
+func TestCommandErrorJSONProgress(t *testing.T) {
+	data, err := os.ReadFile("testdata/stderr/invalid_compose_file.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		cmd.Stderr.Write(data)
+		return &testExitError{code: 15}
+	}
+	c := NewCompose(testProject(), WithRunner(runner), WithProgress(ProgressJSON))
+	err = c.Up(context.Background(), func(opt *ComposeUpOptions) error {
+		opt.Writer = io.Discard
+		return nil
+	})
+	assert.True(t, IsComposeUpError(err))
+	assert.ErrorIs(t, err, ErrInvalidComposeFile)
+	assert.Contains(t, err.Error(), ": validating /dev/stdin: services.web.healthcheck.interval Does not match format 'duration'")
+
+	var cmdErr *CommandError
+	if assert.ErrorAs(t, err, &cmdErr) {
+		assert.Equal(t, "validating /dev/stdin: services.web.healthcheck.interval Does not match format 'duration'\n", cmdErr.Stderr)
+	}
+}
+
+func TestCommandError(t *testing.T) {
+	data, err := os.ReadFile("testdata/stderr/port_allocated.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		cmd.Stderr.Write(data)
+		return &testExitError{code: 1}
+	}
+	out := &bytes.Buffer{}
+	c := NewCompose(testProject(), WithRunner(runner))
+	err = c.Up(context.Background(), func(opt *ComposeUpOptions) error {
+		opt.Writer = out
+		return nil
+	})
+	assert.True(t, IsComposeUpError(err))
+	assert.True(t, IsCommandError(err))
+	assert.ErrorIs(t, err, ErrPortAlreadyAllocated)
+	assert.NotErrorIs(t, err, ErrImageNotFound)
+	assert.Contains(t, err.Error(), "Bind for 0.0.0.0:8080 failed: port is already allocated")
+	assert.Equal(t, string(data), out.String())
+
+	code, ok := ExitCode(err)
+	assert.True(t, ok)
+	assert.Equal(t, 1, code)
+
+	var cmdErr *CommandError
+	if assert.ErrorAs(t, err, &cmdErr) {
+		assert.Equal(t, 1, cmdErr.ExitCode)
+		assert.Equal(t, string(data), cmdErr.Stderr)
+		assert.Equal(t, []string{"docker", "compose", "-f", "-", "up"}, cmdErr.Args)
+	}
+
+	_, ok = ExitCode(errors.New("not a process error"))
+	assert.False(t, ok)
+}
+
+func TestTailBuffer(t *testing.T) {
+	b := &tailBuffer{max: 8}
+	b.Write([]byte("hello "))
+	b.Write([]byte("world!"))
+	assert.Equal(t, "o world!", b.String())
+}
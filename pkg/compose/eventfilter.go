@@ -0,0 +1,142 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+// ErrEventsClosed is returned by WaitFor when the events channel is closed before a matching event is received
+var ErrEventsClosed = errors.New("compose events channel closed")
+
+// EventFilter reports whether an event matches
+type EventFilter func(Events) bool
+
+// ByService matches events of any of the given services
+func ByService(services ...string) EventFilter {
+	return func(e Events) bool {
+		return slices.Contains(services, e.Service)
+	}
+}
+
+// ByAction matches events with any of the given actions.
+// Actions are compared without their detail, so EventActionHealthStatus matches "health_status: healthy".
+func ByAction(actions ...EventAction) EventFilter {
+	return func(e Events) bool {
+		return slices.Contains(actions, e.BaseAction())
+	}
+}
+
+// ByType matches events of any of the given types
+func ByType(types ...EventType) EventFilter {
+	return func(e Events) bool {
+		return slices.Contains(types, e.Type)
+	}
+}
+
+// And matches events that match all filters
+func And(filters ...EventFilter) EventFilter {
+	return func(e Events) bool {
+		for _, filter := range filters {
+			if !filter(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or matches events that match at least one filter
+func Or(filters ...EventFilter) EventFilter {
+	return func(e Events) bool {
+		for _, filter := range filters {
+			if filter(e) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not matches events that do not match the filter
+func Not(filter EventFilter) EventFilter {
+	return func(e Events) bool {
+		return !filter(e)
+	}
+}
+
+// ServiceHealthy matches the health_status event of the service becoming healthy
+func ServiceHealthy(service string) EventFilter {
+	return And(ByService(service), func(e Events) bool {
+		return e.Health() == HealthHealthy
+	})
+}
+
+// ServiceUnhealthy matches the health_status event of the service becoming unhealthy
+func ServiceUnhealthy(service string) EventFilter {
+	return And(ByService(service), func(e Events) bool {
+		return e.Health() == HealthUnhealthy
+	})
+}
+
+// ServiceStarted matches the start event of a container of the service
+func ServiceStarted(service string) EventFilter {
+	return And(ByService(service), ByType(EventTypeContainer), ByAction(EventActionStart))
+}
+
+// ServiceExited matches the die event of a container of the service
+func ServiceExited(service string) EventFilter {
+	return And(ByService(service), ByType(EventTypeContainer), ByAction(EventActionDie))
+}
+
+// FilterEvents forwards the events that match all filters to the returned channel.
+// The returned channel is closed when events is closed or the context is done.
+func FilterEvents(ctx context.Context, events <-chan Events, filters ...EventFilter) <-chan Events {
+	match := And(filters...)
+	out := make(chan Events, 1)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case e, ok := <-events:
+				if !ok {
+					return
+				}
+				if !match(e) {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case out <- e:
+				}
+			}
+		}
+	}()
+	return out
+}
+
+// WaitFor blocks until an event matching all filters is received and returns it.
+// It returns the context error when the context is done, or ErrEventsClosed when events is closed first.
+//
+//	events, errCh, err := app.Events(ctx, "")
+//	...
+//	_, err = compose.WaitFor(ctx, events, compose.ServiceHealthy("db"))
+func WaitFor(ctx context.Context, events <-chan Events, filters ...EventFilter) (Events, error) {
+	match := And(filters...)
+	for {
+		select {
+		case <-ctx.Done():
+			return Events{}, ctx.Err()
+		case e, ok := <-events:
+			if !ok {
+				return Events{}, ErrEventsClosed
+			}
+			if match(e) {
+				return e, nil
+			}
+		}
+	}
+}
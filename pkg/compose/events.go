@@ -5,15 +5,68 @@ import (
 	"context"
 	"encoding/json"
 	"io"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// EventType is the type of object a compose event is about
+type EventType string
+
+const (
+	EventTypeContainer EventType = "container"
+	EventTypeImage     EventType = "image"
+	EventTypeNetwork   EventType = "network"
+	EventTypeVolume    EventType = "volume"
+)
+
+// EventAction is the action of a compose event.
+// Some actions carry a detail after a colon, e.g. "health_status: healthy" or "exec_start: sh -c ls",
+// use Events.BaseAction to compare them with the constants below.
+type EventAction string
+
+const (
+	EventActionAttach       EventAction = "attach"
+	EventActionCommit       EventAction = "commit"
+	EventActionCopy         EventAction = "copy"
+	EventActionCreate       EventAction = "create"
+	EventActionDestroy      EventAction = "destroy"
+	EventActionDetach       EventAction = "detach"
+	EventActionDie          EventAction = "die"
+	EventActionExecCreate   EventAction = "exec_create"
+	EventActionExecDetach   EventAction = "exec_detach"
+	EventActionExecDie      EventAction = "exec_die"
+	EventActionExecStart    EventAction = "exec_start"
+	EventActionExport       EventAction = "export"
+	EventActionHealthStatus EventAction = "health_status"
+	EventActionKill         EventAction = "kill"
+	EventActionOOM          EventAction = "oom"
+	EventActionPause        EventAction = "pause"
+	EventActionRename       EventAction = "rename"
+	EventActionResize       EventAction = "resize"
+	EventActionRestart      EventAction = "restart"
+	EventActionStart        EventAction = "start"
+	EventActionStop         EventAction = "stop"
+	EventActionTop          EventAction = "top"
+	EventActionUnpause      EventAction = "unpause"
+	EventActionUpdate       EventAction = "update"
+)
+
+// Health statuses reported by health_status events
+const (
+	HealthStarting  = "starting"
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Events is a single event reported by docker compose events --json
 type Events struct {
 	// Core event fields
-	Time    string `json:"time"`
-	Type    string `json:"type"`
-	Action  string `json:"action"`
-	ID      string `json:"id"`
-	Service string `json:"service"`
+	Time    time.Time   `json:"time"`
+	Type    EventType   `json:"type"`
+	Action  EventAction `json:"action"`
+	ID      string      `json:"id"`
+	Service string      `json:"service"`
 	// Attributes map containing event-specific information
 	Attributes map[string]string `json:"attributes"`
 
@@ -23,6 +76,37 @@ type Events struct {
 	From   string `json:"from,omitempty"`
 }
 
+// BaseAction returns the action without its detail, e.g. health_status for "health_status: healthy"
+func (e Events) BaseAction() EventAction {
+	action, _, _ := strings.Cut(string(e.Action), ":")
+	return EventAction(strings.TrimSpace(action))
+}
+
+// Health returns the health status of a health_status event, or an empty string for other events
+func (e Events) Health() string {
+	if e.BaseAction() != EventActionHealthStatus {
+		return ""
+	}
+	if _, status, ok := strings.Cut(string(e.Action), ":"); ok {
+		return strings.TrimSpace(status)
+	}
+	return e.Attributes["health_status"]
+}
+
+// ExitCode returns the exit code of a die event.
+// ok is false when the event does not carry an exit code.
+func (e Events) ExitCode() (code int, ok bool) {
+	value, found := e.Attributes["exitCode"]
+	if !found {
+		return 0, false
+	}
+	code, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return code, true
+}
+
 type eventsWriter struct {
 	ctx    context.Context
 	ch     chan Events
@@ -41,6 +125,7 @@ func (w *eventsWriter) Write(p []byte) (n int, err error) {
 		line, err := w.buffer.ReadBytes('\n')
 		if err == io.EOF {
 			// Incomplete line; keep in buffer
+			w.buffer.Write(line)
 			break
 		} else if err != nil {
 			return n, err
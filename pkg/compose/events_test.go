@@ -0,0 +1,115 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readTestEvents(t *testing.T) []Events {
+	data, err := os.ReadFile("testdata/events.json")
+	if !assert.NoError(t, err) {
+		return nil
+	}
+	ch := make(chan Events, 10)
+	errCh := make(chan error, 10)
+	w := newEventsWriter(context.Background(), ch, errCh)
+	// write in small chunks to exercise partial line buffering
+	for i := 0; i < len(data); i += 13 {
+		end := min(i+13, len(data))
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	close(ch)
+	close(errCh)
+	for err := range errCh {
+		assert.NoError(t, err)
+	}
+	events := []Events{}
+	for e := range ch {
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestEventsWriter(t *testing.T) {
+	events := readTestEvents(t)
+	if !assert.Len(t, events, 6) {
+		return
+	}
+	healthy := events[3]
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 12, 49, 0, time.UTC), healthy.Time)
+	assert.Equal(t, EventTypeContainer, healthy.Type)
+	assert.Equal(t, EventActionHealthStatus, healthy.BaseAction())
+	assert.Equal(t, HealthHealthy, healthy.Health())
+	_, ok := healthy.ExitCode()
+	assert.False(t, ok)
+
+	died := events[5]
+	assert.Equal(t, EventActionDie, died.BaseAction())
+	assert.Empty(t, died.Health())
+	code, ok := died.ExitCode()
+	assert.True(t, ok)
+	assert.Equal(t, 137, code)
+}
+
+func TestEventFilters(t *testing.T) {
+	events := readTestEvents(t)
+	tests := []struct {
+		filter   EventFilter
+		message  string
+		expected []int
+	}{
+		{filter: ByService("web"), message: "by service", expected: []int{4, 5}},
+		{filter: ByAction(EventActionStart, EventActionDie), message: "by action", expected: []int{1, 4, 5}},
+		{filter: ByAction(EventActionHealthStatus), message: "by action with detail", expected: []int{2, 3}},
+		{filter: ByType(EventTypeNetwork), message: "by type", expected: []int{}},
+		{filter: And(ByService("db"), Not(ByAction(EventActionHealthStatus))), message: "and not", expected: []int{0, 1}},
+		{filter: Or(ServiceHealthy("db"), ServiceExited("web")), message: "or", expected: []int{3, 5}},
+		{filter: ServiceStarted("web"), message: "service started", expected: []int{4}},
+		{filter: ServiceUnhealthy("db"), message: "service unhealthy", expected: []int{}},
+	}
+	for _, tt := range tests {
+		matched := []int{}
+		for i, e := range events {
+			if tt.filter(e) {
+				matched = append(matched, i)
+			}
+		}
+		assert.Equal(t, tt.expected, matched, tt.message)
+	}
+}
+
+func TestWaitFor(t *testing.T) {
+	events := readTestEvents(t)
+	feed := func() <-chan Events {
+		ch := make(chan Events, len(events))
+		for _, e := range events {
+			ch <- e
+		}
+		close(ch)
+		return ch
+	}
+	ctx := context.Background()
+
+	e, err := WaitFor(ctx, feed(), ServiceHealthy("db"))
+	assert.NoError(t, err)
+	assert.Equal(t, "health_status: healthy", string(e.Action))
+
+	_, err = WaitFor(ctx, feed(), ServiceUnhealthy("db"))
+	assert.ErrorIs(t, err, ErrEventsClosed)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = WaitFor(canceled, make(chan Events), ServiceHealthy("db"))
+	assert.ErrorIs(t, err, context.Canceled)
+
+	filtered := []Events{}
+	for e := range FilterEvents(ctx, feed(), ByService("web")) {
+		filtered = append(filtered, e)
+	}
+	assert.Equal(t, events[4:], filtered)
+}
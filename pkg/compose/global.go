@@ -0,0 +1,172 @@
+package compose
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// globalOptions are the top level docker compose flags and environment applied to every command
+type globalOptions struct {
+	projectDirectory string
+	projectName      string
+	envFiles         []string
+	parallel         *int
+	ansi             string
+	progress         string
+	env              []string
+}
+
+// projectFlags returns the flags that only apply to commands operating on the project
+func (g *globalOptions) projectFlags() []string {
+	flags := []string{}
+	if g.projectDirectory != "" {
+		flags = append(flags, "--project-directory", g.projectDirectory)
+	}
+	if g.projectName != "" {
+		flags = append(flags, "--project-name", g.projectName)
+	}
+	for _, file := range g.envFiles {
+		flags = append(flags, "--env-file", file)
+	}
+	if g.parallel != nil {
+		flags = append(flags, "--parallel", strconv.Itoa(*g.parallel))
+	}
+	return flags
+}
+
+// outputFlags returns the flags that control the output of every command
+func (g *globalOptions) outputFlags() []string {
+	flags := []string{}
+	if g.ansi != "" {
+		flags = append(flags, "--ansi", g.ansi)
+	}
+	if g.progress != "" {
+		flags = append(flags, "--progress", g.progress)
+	}
+	return flags
+}
+
+// environ returns the environment of the compose process,
+// nil when no variables are injected so the process inherits the current environment
+func (g *globalOptions) environ() []string {
+	if len(g.env) == 0 {
+		return nil
+	}
+	return append(os.Environ(), g.env...)
+}
+
+// WithProjectDirectory sets the working directory compose resolves relative paths against
+// (bind mounts, build contexts, env files) instead of the working directory of the Go process
+//
+// --project-directory		Specify an alternate working directory
+func WithProjectDirectory(dir string) SetComposeOption {
+	return func(c *compose) error {
+		c.global.projectDirectory = dir
+		return nil
+	}
+}
+
+// WithProjectName overrides the project name of the generated compose file
+//
+// --project-name		Project name
+func WithProjectName(name string) SetComposeOption {
+	return func(c *compose) error {
+		if name == "" {
+			return NewComposeError(fmt.Errorf("WithProjectName: project name is required"))
+		}
+		c.global.projectName = name
+		return nil
+	}
+}
+
+// WithEnvFile adds alternate environment files, can be used multiple times
+//
+// --env-file		Specify an alternate environment file
+func WithEnvFile(paths ...string) SetComposeOption {
+	return func(c *compose) error {
+		c.global.envFiles = append(c.global.envFiles, paths...)
+		return nil
+	}
+}
+
+// WithParallel sets the maximum parallelism, -1 for unlimited
+//
+// --parallel		Control max parallelism
+func WithParallel(max int) SetComposeOption {
+	return func(c *compose) error {
+		if max == 0 || max < -1 {
+			return NewComposeError(fmt.Errorf("WithParallel: parallelism must be -1 or greater than 0"))
+		}
+		c.global.parallel = &max
+		return nil
+	}
+}
+
+type AnsiMode string
+
+const (
+	AnsiNever  AnsiMode = "never"
+	AnsiAlways AnsiMode = "always"
+	AnsiAuto   AnsiMode = "auto"
+)
+
+// WithAnsi controls when to print ANSI control characters
+//
+// --ansi		Control when to print ANSI control characters ("never"|"always"|"auto")
+func WithAnsi(mode AnsiMode) SetComposeOption {
+	return func(c *compose) error {
+		switch mode {
+		case AnsiNever, AnsiAlways, AnsiAuto:
+		default:
+			return NewComposeError(fmt.Errorf("WithAnsi: invalid mode %q", mode))
+		}
+		c.global.ansi = string(mode)
+		return nil
+	}
+}
+
+type ProgressMode string
+
+const (
+	ProgressAuto  ProgressMode = "auto"
+	ProgressTTY   ProgressMode = "tty"
+	ProgressPlain ProgressMode = "plain"
+	ProgressJSON  ProgressMode = "json"
+	ProgressQuiet ProgressMode = "quiet"
+)
+
+// WithProgress sets the type of progress output
+//
+// --progress		Set type of progress output ("auto"|"tty"|"plain"|"json"|"quiet")
+func WithProgress(mode ProgressMode) SetComposeOption {
+	return func(c *compose) error {
+		switch mode {
+		case ProgressAuto, ProgressTTY, ProgressPlain, ProgressJSON, ProgressQuiet:
+		default:
+			return NewComposeError(fmt.Errorf("WithProgress: invalid mode %q", mode))
+		}
+		c.global.progress = string(mode)
+		return nil
+	}
+}
+
+// WithDockerHost sets DOCKER_HOST for the compose process to target another daemon
+// (e.g. "ssh://user@remote" or "tcp://10.0.0.2:2376"), it takes precedence over the docker context
+func WithDockerHost(host string) SetComposeOption {
+	return WithEnv("DOCKER_HOST=" + host)
+}
+
+// WithDockerContext sets DOCKER_CONTEXT for the compose process to use another docker context
+func WithDockerContext(name string) SetComposeOption {
+	return WithEnv("DOCKER_CONTEXT=" + name)
+}
+
+// WithEnv adds environment variables (key=value) to the compose process,
+// the environment of the Go process is inherited and the given variables take precedence
+func WithEnv(keyValue ...string) SetComposeOption {
+	return func(c *compose) error {
+		c.global.env = append(c.global.env, keyValue...)
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGlobalOptions(t *testing.T) {
+	runner := NewRecordingRunner()
+	c := NewCompose(testProject(),
+		WithRunner(runner),
+		WithProjectDirectory("/srv/demo"),
+		WithProjectName("demo-staging"),
+		WithEnvFile(".env", ".env.staging"),
+		WithParallel(4),
+		WithAnsi(AnsiNever),
+		WithProgress(ProgressPlain),
+		WithDockerHost("ssh://deploy@staging"),
+		WithEnv("COMPOSE_HTTP_TIMEOUT=120"),
+	)
+	assert.NoError(t, c.Down(context.Background()))
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{
+		"docker", "compose",
+		"--project-directory", "/srv/demo",
+		"--project-name", "demo-staging",
+		"--env-file", ".env", "--env-file", ".env.staging",
+		"--parallel", "4",
+		"--ansi", "never",
+		"--progress", "plain",
+		"-f", "-", "down",
+	}, cmd.Argv)
+	assert.Contains(t, cmd.Env, "DOCKER_HOST=ssh://deploy@staging")
+	assert.Contains(t, cmd.Env, "COMPOSE_HTTP_TIMEOUT=120")
+
+	_, err := c.Ls(context.Background())
+	assert.NoError(t, err)
+	cmd, _ = runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "--ansi", "never", "--progress", "plain", "ls", "--format", "json"}, cmd.Argv)
+	assert.Contains(t, cmd.Env, "DOCKER_HOST=ssh://deploy@staging")
+}
+
+func TestGlobalOptionsInvalid(t *testing.T) {
+	for _, setter := range []SetComposeOption{WithParallel(0), WithAnsi("sometimes"), WithProgress("fancy"), WithProjectName("")} {
+		c := NewCompose(testProject(), WithRunner(NewRecordingRunner()), setter)
+		assert.True(t, IsComposeDownError(c.Down(context.Background())))
+	}
+}
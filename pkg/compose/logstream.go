@@ -0,0 +1,200 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// LogStream is the output stream a log line was written to by the container
+type LogStream string
+
+const (
+	LogStreamStdout LogStream = "stdout"
+	LogStreamStderr LogStream = "stderr"
+)
+
+// LogLine is a single parsed line of docker compose logs
+type LogLine struct {
+	// Service is the service the line belongs to, empty when the prefix is disabled or unknown
+	Service string
+	// Container is the log prefix printed by compose, e.g. "web-1" or the container_name
+	Container string
+	Stream    LogStream
+	// Timestamp is only set when logs.WithTimestamps is used
+	Timestamp time.Time
+	Message   string
+}
+
+var replicaSuffix = regexp.MustCompile(`^(.+)-(\d+)$`)
+
+// LogStream runs the docker compose logs command and streams parsed lines on the returned channel.
+// It accepts the same options as Logs, the writer set with logs.WithWriter is not used.
+// Both channels are closed when the command exits, use logs.WithFollow to keep streaming until the context is canceled.
+func (c *compose) LogStream(ctx context.Context, setters ...SetComposeLogsOption) (<-chan LogLine, <-chan error, error) {
+	opt := &ComposeLogsOptions{
+		Flags:  []string{"logs"},
+		Writer: io.Discard,
+	}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, nil, NewComposeLogsError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, nil, NewComposeLogsError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, nil, NewComposeLogsError(err)
+	}
+	linesCh := make(chan LogLine, 1)
+	errCh := make(chan error, 1)
+
+	parser := &logParser{
+		services:   c.serviceNames(),
+		prefix:     !opt.NoLogPrefix,
+		timestamps: opt.Timestamps,
+	}
+	cmd.Stdout = newLogWriter(ctx, linesCh, parser, LogStreamStdout)
+	cmd.Stderr = newLogWriter(ctx, linesCh, parser, LogStreamStderr)
+
+	go func() {
+		defer close(linesCh)
+		defer close(errCh)
+
+		if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+			errCh <- NewComposeLogsError(err)
+		}
+	}()
+
+	return linesCh, errCh, nil
+}
+
+// serviceNames returns the set of service names of the project
+func (c *compose) serviceNames() map[string]struct{} {
+	names := map[string]struct{}{}
+	c.project.ForEachService(func(name string, _ *types.ServiceConfig) error {
+		names[name] = struct{}{}
+		return nil
+	})
+	return names
+}
+
+// FanOutLogs writes the message of every line to the writer of its service until lines is closed.
+// Lines of services without a writer are written to fallback, or dropped when fallback is nil.
+// It returns the joined write errors, a failing writer does not stop the other services.
+func FanOutLogs(lines <-chan LogLine, writers map[string]io.Writer, fallback io.Writer) error {
+	var errs []error
+	failed := map[io.Writer]bool{}
+	for line := range lines {
+		w, ok := writers[line.Service]
+		if !ok {
+			w = fallback
+		}
+		if w == nil || failed[w] {
+			continue
+		}
+		if _, err := io.WriteString(w, line.Message+"\n"); err != nil {
+			failed[w] = true
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// logParser parses output lines of docker compose logs
+type logParser struct {
+	services   map[string]struct{}
+	prefix     bool
+	timestamps bool
+}
+
+// parse parses a single line, the trailing newline must already be removed
+func (p *logParser) parse(line string, stream LogStream) LogLine {
+	line = ansiEscape.ReplaceAllString(line, "")
+	l := LogLine{Stream: stream, Message: line}
+	if p.prefix {
+		if prefix, message, ok := strings.Cut(line, " | "); ok {
+			l.Container = strings.TrimSpace(prefix)
+			l.Service = p.service(l.Container)
+			l.Message = message
+		}
+	}
+	if p.timestamps {
+		if ts, message, ok := strings.Cut(l.Message, " "); ok {
+			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+				l.Timestamp = t
+				l.Message = message
+			}
+		}
+	}
+	return l
+}
+
+// service resolves the service of a log prefix, which is either the container_name of the
+// service or the container name without the project, e.g. "web-1"
+func (p *logParser) service(container string) string {
+	if _, ok := p.services[container]; ok {
+		return container
+	}
+	if m := replicaSuffix.FindStringSubmatch(container); m != nil {
+		name := m[1]
+		if _, ok := p.services[name]; ok {
+			return name
+		}
+		// older compose versions keep the project in the prefix, e.g. "demo-web-1"
+		for service := range p.services {
+			if strings.HasSuffix(name, "-"+service) {
+				return service
+			}
+		}
+		return name
+	}
+	return container
+}
+
+type logWriter struct {
+	ctx    context.Context
+	ch     chan LogLine
+	parser *logParser
+	stream LogStream
+	buffer bytes.Buffer
+}
+
+func (w *logWriter) Write(p []byte) (n int, err error) {
+	n, err = w.buffer.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	for {
+		line, err := w.buffer.ReadBytes('\n')
+		if err == io.EOF {
+			// Incomplete line; keep in buffer
+			w.buffer.Write(line)
+			break
+		} else if err != nil {
+			return n, err
+		}
+		select {
+		case <-w.ctx.Done():
+			w.buffer.Reset()
+			return n, w.ctx.Err()
+		case w.ch <- w.parser.parse(strings.TrimRight(string(line), "\r\n"), w.stream):
+		}
+	}
+
+	return n, nil
+}
+
+func newLogWriter(ctx context.Context, ch chan LogLine, parser *logParser, stream LogStream) io.Writer {
+	return &logWriter{ctx: ctx, ch: ch, parser: parser, stream: stream}
+}
@@ -0,0 +1,147 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogWriter(t *testing.T) {
+	data, err := os.ReadFile("testdata/logs.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	parser := &logParser{
+		services:   map[string]struct{}{"web": {}, "db": {}, "worker": {}},
+		prefix:     true,
+		timestamps: true,
+	}
+	ch := make(chan LogLine, 10)
+	w := newLogWriter(context.Background(), ch, parser, LogStreamStdout)
+	// write in small chunks to exercise partial line buffering
+	for i := 0; i < len(data); i += 11 {
+		end := min(i+11, len(data))
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	close(ch)
+	lines := []LogLine{}
+	for l := range ch {
+		lines = append(lines, l)
+	}
+	assert.Equal(t, []LogLine{
+		{
+			Service:   "web",
+			Container: "web-1",
+			Stream:    LogStreamStdout,
+			Timestamp: time.Date(2025, 6, 1, 10, 12, 44, 123456789, time.UTC),
+			Message:   "/docker-entrypoint.sh: Configuration complete; ready for start up",
+		},
+		{
+			Service:   "web",
+			Container: "web-2",
+			Stream:    LogStreamStdout,
+			Timestamp: time.Date(2025, 6, 1, 10, 12, 45, 0, time.UTC),
+			Message:   `172.18.0.1 - - "GET / HTTP/1.1" 200 615`,
+		},
+		{
+			Service:   "db",
+			Container: "demo-db-1",
+			Stream:    LogStreamStdout,
+			Timestamp: time.Date(2025, 6, 1, 10, 12, 46, 500000000, time.UTC),
+			Message:   "LOG:  database system is ready to accept connections",
+		},
+		{
+			Service:   "worker",
+			Container: "worker",
+			Stream:    LogStreamStdout,
+			Timestamp: time.Date(2025, 6, 1, 10, 12, 47, 0, time.UTC),
+			Message:   "job | done",
+		},
+	}, lines)
+}
+
+func TestLogParser(t *testing.T) {
+	tests := []struct {
+		parser   *logParser
+		line     string
+		message  string
+		expected LogLine
+	}{
+		{
+			parser:   &logParser{prefix: false, timestamps: false},
+			line:     "web-1  | hello",
+			message:  "no log prefix keeps the line as is",
+			expected: LogLine{Stream: LogStreamStderr, Message: "web-1  | hello"},
+		},
+		{
+			parser:   &logParser{prefix: false, timestamps: true},
+			line:     "2025-06-01T10:12:44Z hello world",
+			message:  "timestamps without prefix",
+			expected: LogLine{Stream: LogStreamStderr, Timestamp: time.Date(2025, 6, 1, 10, 12, 44, 0, time.UTC), Message: "hello world"},
+		},
+		{
+			parser:   &logParser{prefix: true, timestamps: true},
+			line:     "\x1b[36mapi-1  | \x1b[0mnot-a-time hello",
+			message:  "colored prefix of an unknown service and invalid timestamp",
+			expected: LogLine{Service: "api", Container: "api-1", Stream: LogStreamStderr, Message: "not-a-time hello"},
+		},
+		{
+			parser:   &logParser{prefix: true},
+			line:     "Error response from daemon: no such container",
+			message:  "line without prefix",
+			expected: LogLine{Stream: LogStreamStderr, Message: "Error response from daemon: no such container"},
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, tt.parser.parse(tt.line, LogStreamStderr), tt.message)
+	}
+}
+
+func TestLogStream(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		io.WriteString(cmd.Stdout, "web  | 2025-06-01T10:12:44Z started\n")
+		io.WriteString(cmd.Stderr, "web  | 2025-06-01T10:12:45Z warning\n")
+		return nil
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+	lines, errs, err := c.LogStream(context.Background(), func(opt *ComposeLogsOptions) error {
+		opt.Timestamps = true
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	web := &bytes.Buffer{}
+	assert.NoError(t, FanOutLogs(lines, map[string]io.Writer{"web": web}, nil))
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, "started\nwarning\n", web.String())
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "logs", "--timestamps"}, cmd.Argv)
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("write failed") }
+
+func TestFanOutLogs(t *testing.T) {
+	lines := make(chan LogLine, 4)
+	lines <- LogLine{Service: "web", Message: "a"}
+	lines <- LogLine{Service: "db", Message: "b"}
+	lines <- LogLine{Service: "web", Message: "c"}
+	lines <- LogLine{Service: "cache", Message: "d"}
+	close(lines)
+	rest := &bytes.Buffer{}
+	err := FanOutLogs(lines, map[string]io.Writer{"web": failingWriter{}}, rest)
+	assert.EqualError(t, err, "write failed")
+	assert.Equal(t, "b\nd\n", rest.String())
+}
@@ -0,0 +1,91 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ProjectSummary is a compose project as reported by docker compose ls --format json
+type ProjectSummary struct {
+	Name string `json:"Name"`
+	// Status is the aggregated container status of the project, e.g. "running(2)" or "exited(1), running(1)"
+	Status string `json:"Status"`
+	// ConfigFiles is the comma separated list of compose files the project was created from
+	ConfigFiles string `json:"ConfigFiles"`
+}
+
+// ServiceImage is an image used by a service container as reported by docker compose images --format json
+type ServiceImage struct {
+	ID            string    `json:"ID"`
+	ContainerName string    `json:"ContainerName"`
+	Repository    string    `json:"Repository"`
+	Tag           string    `json:"Tag"`
+	Platform      string    `json:"Platform"`
+	Size          int64     `json:"Size"`
+	LastTagTime   time.Time `json:"LastTagTime"`
+}
+
+// Ls runs the docker compose ls command and returns the compose projects on the host.
+// It does not depend on the project the compose instance was created with.
+// The writer set with ls.WithWriter only receives stderr output.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) Ls(ctx context.Context, setters ...SetComposeLsOption) ([]ProjectSummary, error) {
+	opt := &ComposeLsOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposeLsError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposeLsError(err)
+	}
+	cmd, err := c.globalCommand(opt.Writer, flags)
+	if err != nil {
+		return nil, NewComposeLsError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposeLsError(err)
+	}
+	projects, err := decodeJSONList[ProjectSummary](stdout.Bytes())
+	if err != nil {
+		return nil, NewComposeLsError(fmt.Errorf("failed to parse ls output: %w", err))
+	}
+	return projects, nil
+}
+
+// Images runs the docker compose images command and returns the images used by the project containers.
+// The writer set with images.WithWriter only receives stderr output.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) Images(ctx context.Context, setters ...SetComposeImagesOption) ([]ServiceImage, error) {
+	opt := &ComposeImagesOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposeImagesError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposeImagesError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, NewComposeImagesError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposeImagesError(err)
+	}
+	images, err := decodeJSONList[ServiceImage](stdout.Bytes())
+	if err != nil {
+		return nil, NewComposeImagesError(fmt.Errorf("failed to parse images output: %w", err))
+	}
+	return images, nil
+}
@@ -0,0 +1,40 @@
+package compose
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeLsOutput(t *testing.T) {
+	data, err := os.ReadFile("testdata/ls.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	projects, err := decodeJSONList[ProjectSummary](data)
+	assert.NoError(t, err)
+	assert.Equal(t, []ProjectSummary{
+		{Name: "demo", Status: "running(2)", ConfigFiles: "-"},
+		{Name: "supabase", Status: "exited(1), running(12)", ConfigFiles: "/srv/supabase/docker-compose.yml,/srv/supabase/docker-compose.override.yml"},
+	}, projects)
+}
+
+func TestDecodeImagesOutput(t *testing.T) {
+	data, err := os.ReadFile("testdata/images.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	images, err := decodeJSONList[ServiceImage](data)
+	if !assert.NoError(t, err) || !assert.Len(t, images, 2) {
+		return
+	}
+	assert.Equal(t, "demo-web-1", images[0].ContainerName)
+	assert.Equal(t, "nginx", images[0].Repository)
+	assert.Equal(t, "alpine", images[0].Tag)
+	assert.Equal(t, int64(52408320), images[0].Size)
+	assert.True(t, images[0].LastTagTime.IsZero())
+	assert.Equal(t, "linux/arm64", images[1].Platform)
+	assert.Equal(t, time.Date(2025, 6, 1, 10, 12, 40, 123456789, time.UTC), images[1].LastTagTime)
+}
@@ -0,0 +1,79 @@
+// Package attach provides options for the compose attach command
+package attach
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithService sets the service name (required)
+func WithService(service string) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.Service = service
+		return nil
+	}
+}
+
+// WithDetachKeys overrides the key sequence for detaching from a container (e.g. "ctrl-p,ctrl-q")
+func WithDetachKeys(keys string) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.DetachKeys = keys
+		return nil
+	}
+}
+
+// WithIndex sets the index of the container when the service has multiple replicas
+func WithIndex(index int) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.Index = &index
+		return nil
+	}
+}
+
+// WithNoStdin does not attach stdin
+//
+// note: cannot be used together with WithStdin
+func WithNoStdin() compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.NoStdin = true
+		return nil
+	}
+}
+
+// WithSigProxy sets whether all received signals are proxied to the process (default true)
+func WithSigProxy(enabled bool) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.SigProxy = &enabled
+		return nil
+	}
+}
+
+// WithStdin sets the reader to forward to the service's main process (e.g. os.Stdin for interactive)
+func WithStdin(r io.Reader) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.Stdin = r
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeAttachOption {
+	return func(opt *compose.ComposeAttachOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
@@ -139,3 +139,12 @@ func WithServiceNames(names ...string) compose.SetComposeBuildOption {
 		return nil
 	}
 }
+
+// WithProgress reports parsed progress events to fn by running compose with --progress json.
+// The writer still receives the progress as plain text.
+func WithProgress(fn compose.ProgressFunc) compose.SetComposeBuildOption {
+	return func(opt *compose.ComposeBuildOptions) error {
+		opt.Progress = fn
+		return nil
+	}
+}
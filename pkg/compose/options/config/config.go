@@ -0,0 +1,104 @@
+// Package config provides options for the compose config command
+package config
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatJSON Format = "json"
+)
+
+// WithFormat sets the format of the raw output (default yaml)
+func WithFormat(format Format) compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.Format = string(format)
+		return nil
+	}
+}
+
+// WithServices prints the service names, one per line
+//
+// note: the result only has Services set, Project is nil
+func WithServices() compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.Services = true
+		return nil
+	}
+}
+
+// WithVolumes prints the volume names, one per line
+//
+// note: the result only has Volumes set, Project is nil
+func WithVolumes() compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.Volumes = true
+		return nil
+	}
+}
+
+// WithImages prints the image names, one per line
+//
+// note: the result only has Images set, Project is nil
+func WithImages() compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.Images = true
+		return nil
+	}
+}
+
+// WithHash prints the service config hash, one per line.
+// When no services are given the hash of every service is printed.
+//
+// note: the result only has Hashes set, Project is nil
+func WithHash(services ...string) compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		if opt.Hash == nil {
+			opt.Hash = []string{}
+		}
+		opt.Hash = append(opt.Hash, services...)
+		return nil
+	}
+}
+
+// WithNoInterpolate does not interpolate environment variables
+func WithNoInterpolate() compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.NoInterpolate = true
+		return nil
+	}
+}
+
+// WithResolveImageDigests pins image tags to digests
+func WithResolveImageDigests() compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.ResolveImageDigests = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stderr
+func WithWriter(writer io.Writer) compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeConfigOption {
+	return func(opt *compose.ComposeConfigOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+// Package cp provides options for the compose cp command
+package cp
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithFrom copies srcPath from the service container to destPath on the host
+//
+// use "-" as destPath to write a tar archive to the writer
+func WithFrom(service, srcPath, destPath string) compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.Source = service + ":" + srcPath
+		opt.Destination = destPath
+		return nil
+	}
+}
+
+// WithTo copies srcPath from the host to destPath in the service container
+func WithTo(srcPath, service, destPath string) compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.Source = srcPath
+		opt.Destination = service + ":" + destPath
+		return nil
+	}
+}
+
+// WithArchive sets the archive mode (copy all uid/gid information)
+func WithArchive() compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.Archive = true
+		return nil
+	}
+}
+
+// WithFollowLink always follows symbol links in the source path
+func WithFollowLink() compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.FollowLink = true
+		return nil
+	}
+}
+
+// WithIndex sets the index of the container when the service has multiple replicas
+func WithIndex(index int) compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.Index = &index
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeCpOption {
+	return func(opt *compose.ComposeCpOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+// Package create provides options for the compose create command
+package create
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+type PullPolicy string
+
+const (
+	PullPolicyAlways  PullPolicy = "always"
+	PullPolicyMissing PullPolicy = "missing"
+	PullPolicyNever   PullPolicy = "never"
+	PullPolicyBuild   PullPolicy = "build"
+)
+
+// WithBuild builds images before starting containers
+//
+// note: cannot be used together with WithNoBuild
+func WithBuild() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.Build = true
+		return nil
+	}
+}
+
+// WithNoBuild does not build an image, even if it's policy
+func WithNoBuild() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.NoBuild = true
+		return nil
+	}
+}
+
+// WithForceRecreate recreates containers even if their configuration and image haven't changed
+//
+// note: cannot be used together with WithNoRecreate
+func WithForceRecreate() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.ForceRecreate = true
+		return nil
+	}
+}
+
+// WithNoRecreate does not recreate containers if they already exist
+func WithNoRecreate() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.NoRecreate = true
+		return nil
+	}
+}
+
+// WithPull sets the pull policy before creating ("always"|"missing"|"never"|"build")
+func WithPull(policy PullPolicy) compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		pull := string(policy)
+		opt.Pull = &pull
+		return nil
+	}
+}
+
+// WithQuietPull pulls without printing progress information
+func WithQuietPull() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.QuietPull = true
+		return nil
+	}
+}
+
+// WithRemoveOrphans removes containers for services not defined in the Compose file
+func WithRemoveOrphans() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.RemoveOrphans = true
+		return nil
+	}
+}
+
+// WithScale scales SERVICE to NUM instances, overriding the scale setting in the Compose file
+func WithScale(service string, num int) compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.Scale = append(opt.Scale, compose.ComposeUpScale{Service: service, Num: num})
+		return nil
+	}
+}
+
+// WithYes assumes "yes" as answer to all prompts and runs non-interactively
+func WithYes() compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.Yes = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names (positional args)
+func WithServiceNames(names ...string) compose.SetComposeCreateOption {
+	return func(opt *compose.ComposeCreateOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
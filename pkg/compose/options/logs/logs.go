@@ -30,6 +30,14 @@ func WithNoLogPrefix() compose.SetComposeLogsOption {
 	}
 }
 
+// WithTimestamps shows timestamps
+func WithTimestamps() compose.SetComposeLogsOption {
+	return func(opt *compose.ComposeLogsOptions) error {
+		opt.Timestamps = true
+		return nil
+	}
+}
+
 // WithWriter sets the writer for the compose logs command stdout and stderr
 //
 // if writer is nil, it will use os.Stdout as a fallback
@@ -0,0 +1,37 @@
+// Package ls provides options for the compose ls command
+package ls
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithAll shows all stopped compose projects
+func WithAll() compose.SetComposeLsOption {
+	return func(opt *compose.ComposeLsOptions) error {
+		opt.All = true
+		return nil
+	}
+}
+
+// WithFilter filters output based on conditions provided (e.g. "name=demo")
+func WithFilter(keyValue string) compose.SetComposeLsOption {
+	return func(opt *compose.ComposeLsOptions) error {
+		opt.Filter = append(opt.Filter, keyValue)
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stderr
+func WithWriter(writer io.Writer) compose.SetComposeLsOption {
+	return func(opt *compose.ComposeLsOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
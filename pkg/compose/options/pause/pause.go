@@ -0,0 +1,37 @@
+// Package pause provides options for the compose pause command
+package pause
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposePauseOption {
+	return func(opt *compose.ComposePauseOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposePauseOption {
+	return func(opt *compose.ComposePauseOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names (positional args)
+func WithServiceNames(names ...string) compose.SetComposePauseOption {
+	return func(opt *compose.ComposePauseOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
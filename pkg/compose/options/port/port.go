@@ -0,0 +1,68 @@
+// Package port provides options for the compose port command
+package port
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+type Protocol string
+
+const (
+	ProtocolTCP Protocol = "tcp"
+	ProtocolUDP Protocol = "udp"
+)
+
+// WithService sets the service name (required)
+func WithService(service string) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		opt.Service = service
+		return nil
+	}
+}
+
+// WithPrivatePort sets the container port to look up (required)
+func WithPrivatePort(port int) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		opt.PrivatePort = port
+		return nil
+	}
+}
+
+// WithIndex sets the index of the container when the service has multiple replicas
+func WithIndex(index int) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		opt.Index = &index
+		return nil
+	}
+}
+
+// WithProtocol sets the protocol of the private port (default tcp)
+func WithProtocol(protocol Protocol) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		opt.Protocol = string(protocol)
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stderr
+func WithWriter(writer io.Writer) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposePortOption {
+	return func(opt *compose.ComposePortOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
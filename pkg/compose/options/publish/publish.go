@@ -0,0 +1,76 @@
+// Package publish provides options for the compose publish command
+package publish
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+type OCIVersion string
+
+const (
+	OCIVersion1_0 OCIVersion = "1.0"
+	OCIVersion1_1 OCIVersion = "1.1"
+)
+
+// WithRepository sets the repository to publish to, e.g. "registry.example.com/stack:1.0" (required)
+func WithRepository(repository string) compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.Repository = repository
+		return nil
+	}
+}
+
+// WithOCIVersion sets the OCI image/artifact specification version (automatically determined by default)
+func WithOCIVersion(version OCIVersion) compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.OCIVersion = string(version)
+		return nil
+	}
+}
+
+// WithResolveImageDigests pins image tags to digests
+func WithResolveImageDigests() compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.ResolveImageDigests = true
+		return nil
+	}
+}
+
+// WithEnv includes environment variables in the published OCI artifact
+func WithEnv() compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.WithEnv = true
+		return nil
+	}
+}
+
+// WithYes assumes "yes" as answer to all prompts
+func WithYes() compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.Yes = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposePublishOption {
+	return func(opt *compose.ComposePublishOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
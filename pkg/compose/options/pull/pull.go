@@ -75,3 +75,12 @@ func WithServiceNames(names ...string) compose.SetComposePullOption {
 		return nil
 	}
 }
+
+// WithProgress reports parsed progress events to fn by running compose with --progress json.
+// The writer still receives the progress as plain text.
+func WithProgress(fn compose.ProgressFunc) compose.SetComposePullOption {
+	return func(opt *compose.ComposePullOptions) error {
+		opt.Progress = fn
+		return nil
+	}
+}
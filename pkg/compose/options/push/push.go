@@ -0,0 +1,61 @@
+// Package push provides options for the compose push command
+package push
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithIgnorePushFailures pushes what it can and ignores images with push failures
+func WithIgnorePushFailures() compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		opt.IgnorePushFailures = true
+		return nil
+	}
+}
+
+// WithIncludeDeps also pushes images of services declared as dependencies
+func WithIncludeDeps() compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		opt.IncludeDeps = true
+		return nil
+	}
+}
+
+// WithQuiet pushes without printing progress information
+func WithQuiet() compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		opt.Quiet = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names (positional args)
+func WithServiceNames(names ...string) compose.SetComposePushOption {
+	return func(opt *compose.ComposePushOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
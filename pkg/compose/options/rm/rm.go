@@ -0,0 +1,62 @@
+// Package rm provides options for the compose rm command
+package rm
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithForce does not ask to confirm removal.
+// It is kept for compatibility, compose rm always runs with --force because stdin carries the compose file.
+func WithForce() compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		opt.Force = true
+		return nil
+	}
+}
+
+// WithStop stops the containers, if required, before removing
+func WithStop() compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		opt.Stop = true
+		return nil
+	}
+}
+
+// WithVolumes removes any anonymous volumes attached to containers
+func WithVolumes() compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		opt.Volumes = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names (positional args)
+func WithServiceNames(names ...string) compose.SetComposeRmOption {
+	return func(opt *compose.ComposeRmOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
@@ -0,0 +1,161 @@
+// Package run provides options for the compose run command
+package run
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithDetach runs the container in the background and prints its id
+func WithDetach() compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Detach = true
+		return nil
+	}
+}
+
+// WithEntrypoint overrides the entrypoint of the image
+func WithEntrypoint(entrypoint string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Entrypoint = &entrypoint
+		return nil
+	}
+}
+
+// WithEnv sets environment variables (key=value), can be used multiple times
+func WithEnv(keyValue ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Env = append(opt.Env, keyValue...)
+		return nil
+	}
+}
+
+// WithLabel adds or overrides a label (key=value), can be used multiple times
+func WithLabel(keyValue ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Label = append(opt.Label, keyValue...)
+		return nil
+	}
+}
+
+// WithName assigns a name to the container
+func WithName(name string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Name = name
+		return nil
+	}
+}
+
+// WithNoDeps does not start linked services
+func WithNoDeps() compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.NoDeps = true
+		return nil
+	}
+}
+
+// WithNoTTY disables pseudo-TTY allocation (use for scripts or piping)
+func WithNoTTY() compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.NoTTY = true
+		return nil
+	}
+}
+
+// WithPublish publishes a container's port(s) to the host (e.g. "8080:80")
+//
+// note: cannot be used together with WithServicePorts
+func WithPublish(ports ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Publish = append(opt.Publish, ports...)
+		return nil
+	}
+}
+
+// WithRemove automatically removes the container when it exits
+func WithRemove() compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Remove = true
+		return nil
+	}
+}
+
+// WithServicePorts runs the command with all the service's ports enabled and mapped to the host
+//
+// note: cannot be used together with WithPublish
+func WithServicePorts() compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.ServicePorts = true
+		return nil
+	}
+}
+
+// WithUser runs the command as this user (e.g. "root", "1000:1000")
+func WithUser(user string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.User = user
+		return nil
+	}
+}
+
+// WithVolume bind mounts a volume (e.g. "./data:/data"), can be used multiple times
+func WithVolume(volumes ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Volumes = append(opt.Volumes, volumes...)
+		return nil
+	}
+}
+
+// WithWorkdir sets the working directory inside the container
+func WithWorkdir(dir string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Workdir = dir
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithStdin sets the reader to forward to the container (e.g. os.Stdin for interactive)
+func WithStdin(r io.Reader) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Stdin = r
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithService sets the service name (required)
+func WithService(service string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Service = service
+		return nil
+	}
+}
+
+// WithCommand sets the command and arguments overriding the service command, e.g. WithCommand("./migrate", "up")
+func WithCommand(command ...string) compose.SetComposeRunOption {
+	return func(opt *compose.ComposeRunOptions) error {
+		opt.Command = append(opt.Command, command...)
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+// Package scale provides options for the compose scale command
+package scale
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithService scales the service to the given number of replicas, can be used multiple times
+func WithService(service string, replicas int) compose.SetComposeScaleOption {
+	return func(opt *compose.ComposeScaleOptions) error {
+		opt.Scale = append(opt.Scale, compose.ComposeUpScale{Service: service, Num: replicas})
+		return nil
+	}
+}
+
+// WithNoDeps does not start linked services
+func WithNoDeps() compose.SetComposeScaleOption {
+	return func(opt *compose.ComposeScaleOptions) error {
+		opt.NoDeps = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeScaleOption {
+	return func(opt *compose.ComposeScaleOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeScaleOption {
+	return func(opt *compose.ComposeScaleOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+// Package stats provides options for the compose stats command
+package stats
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithAll shows all containers (default shows just running)
+func WithAll() compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		opt.All = true
+		return nil
+	}
+}
+
+// WithNoStream disables streaming stats and only pulls the first result
+func WithNoStream() compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		opt.NoStream = true
+		return nil
+	}
+}
+
+// WithNoTrunc does not truncate output
+func WithNoTrunc() compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		opt.NoTrunc = true
+		return nil
+	}
+}
+
+// WithService only streams stats of the given service
+func WithService(service string) compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		opt.Service = service
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stderr
+func WithWriter(writer io.Writer) compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeStatsOption {
+	return func(opt *compose.ComposeStatsOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
@@ -0,0 +1,37 @@
+// Package top provides options for the compose top command
+package top
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithWriter sets the writer for stderr
+func WithWriter(writer io.Writer) compose.SetComposeTopOption {
+	return func(opt *compose.ComposeTopOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeTopOption {
+	return func(opt *compose.ComposeTopOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names (positional args)
+func WithServiceNames(names ...string) compose.SetComposeTopOption {
+	return func(opt *compose.ComposeTopOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
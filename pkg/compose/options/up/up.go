@@ -325,3 +325,12 @@ func WithWriter(writer io.Writer) compose.SetComposeUpOption {
 		return nil
 	}
 }
+
+// WithProgress reports parsed progress events to fn by running compose with --progress json.
+// The writer still receives the progress as plain text.
+func WithProgress(fn compose.ProgressFunc) compose.SetComposeUpOption {
+	return func(opt *compose.ComposeUpOptions) error {
+		opt.Progress = fn
+		return nil
+	}
+}
@@ -0,0 +1,45 @@
+// Package wait provides options for the compose wait command
+package wait
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithDownProject drops the whole project once the first container stops
+func WithDownProject() compose.SetComposeWaitOption {
+	return func(opt *compose.ComposeWaitOptions) error {
+		opt.DownProject = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer for stdout/stderr
+func WithWriter(writer io.Writer) compose.SetComposeWaitOption {
+	return func(opt *compose.ComposeWaitOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeWaitOption {
+	return func(opt *compose.ComposeWaitOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the service names to wait for (required, positional args)
+func WithServiceNames(names ...string) compose.SetComposeWaitOption {
+	return func(opt *compose.ComposeWaitOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
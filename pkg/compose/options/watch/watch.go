@@ -0,0 +1,63 @@
+// Package watch provides options for the compose watch command
+package watch
+
+import (
+	"io"
+	"os"
+
+	"github.com/aptd3v/go-contain/pkg/compose"
+)
+
+// WithNoUp does not build and start services before watching
+func WithNoUp() compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		opt.NoUp = true
+		return nil
+	}
+}
+
+// WithPrune prunes dangling images on rebuild
+func WithPrune() compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		opt.Prune = true
+		return nil
+	}
+}
+
+// WithQuiet hides build output
+func WithQuiet() compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		opt.Quiet = true
+		return nil
+	}
+}
+
+// WithWriter sets the writer that receives the raw stdout/stderr output
+//
+// if writer is nil, it will use os.Stdout as a fallback, use io.Discard to only receive parsed events
+func WithWriter(writer io.Writer) compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		if writer == nil {
+			opt.Writer = os.Stdout
+			return nil
+		}
+		opt.Writer = writer
+		return nil
+	}
+}
+
+// WithProfiles sets the profiles to activate
+func WithProfiles(profiles ...string) compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		opt.Profiles = profiles
+		return nil
+	}
+}
+
+// WithServiceNames sets the optional service names to watch (positional args)
+func WithServiceNames(names ...string) compose.SetComposeWatchOption {
+	return func(opt *compose.ComposeWatchOptions) error {
+		opt.ServiceNames = append(opt.ServiceNames, names...)
+		return nil
+	}
+}
@@ -0,0 +1,74 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PortBinding is the host address a service container port is published on
+type PortBinding struct {
+	HostIP   string
+	HostPort int
+}
+
+// String returns the binding in host:port form
+func (p PortBinding) String() string {
+	return net.JoinHostPort(p.HostIP, strconv.Itoa(p.HostPort))
+}
+
+// Port runs the docker compose port command and returns the public binding of the service's private port.
+// The writer set with port.WithWriter only receives stderr output.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) Port(ctx context.Context, setters ...SetComposePortOption) (*PortBinding, error) {
+	opt := &ComposePortOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposePortError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposePortError(err)
+	}
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, NewComposePortError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposePortError(err)
+	}
+	if c.skipsExecution() {
+		return &PortBinding{}, nil
+	}
+	binding, err := parsePortOutput(stdout.Bytes())
+	if err != nil {
+		return nil, NewComposePortError(err)
+	}
+	return binding, nil
+}
+
+// parsePortOutput parses the host:port output of docker compose port
+func parsePortOutput(data []byte) (*PortBinding, error) {
+	out := strings.TrimSpace(string(data))
+	if out == "" {
+		return nil, fmt.Errorf("port is not published")
+	}
+	// only the first binding is relevant, compose may print one per address family
+	out = strings.Fields(out)[0]
+	host, port, err := net.SplitHostPort(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse port output %q: %w", out, err)
+	}
+	num, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse port output %q: %w", out, err)
+	}
+	return &PortBinding{HostIP: host, HostPort: num}, nil
+}
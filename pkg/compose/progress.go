@@ -0,0 +1,233 @@
+package compose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// ProgressStatus is the state of a resource reported by a progress event
+type ProgressStatus string
+
+const (
+	ProgressStatusWorking ProgressStatus = "working"
+	ProgressStatusDone    ProgressStatus = "done"
+	ProgressStatusWarning ProgressStatus = "warning"
+	ProgressStatusError   ProgressStatus = "error"
+)
+
+// ProgressEvent is a progress update of a single resource reported by docker compose --progress json
+type ProgressEvent struct {
+	// ID is the resource, e.g. "Container demo-web-1", "Network demo_default", a service name or an image layer
+	ID string
+	// ParentID is set for nested resources such as the layers of a pulled image
+	ParentID string
+	// Text is the step reported by compose, e.g. "Pulling", "Created" or "Started"
+	Text string
+	// Detail is the additional status text, e.g. a progress bar or an error message
+	Detail  string
+	Status  ProgressStatus
+	Current int64
+	Total   int64
+	Percent int
+}
+
+// IsDone reports whether the resource finished successfully
+func (e ProgressEvent) IsDone() bool {
+	return e.Status == ProgressStatusDone
+}
+
+// IsError reports whether the resource failed
+func (e ProgressEvent) IsError() bool {
+	return e.Status == ProgressStatusError
+}
+
+// ProgressFunc receives progress events of a compose command.
+// It is called synchronously while the output of compose is read and should return quickly.
+type ProgressFunc func(ProgressEvent)
+
+// progressDoneTexts are the steps compose reports when a resource finished
+var progressDoneTexts = []string{
+	"Already exists", "Built", "Created", "Done", "Download complete", "Exited", "Healthy",
+	"Killed", "Paused", "Pull complete", "Pulled", "Recreated", "Removed", "Restarted",
+	"Running", "Skipped", "Started", "Stopped", "Unpaused",
+}
+
+// progressMessage is a line of docker compose --progress json output.
+// The error a command fails with is reported as a message with only Error and Message set.
+type progressMessage struct {
+	Error    bool   `json:"error,omitempty"`
+	Message  string `json:"message,omitempty"`
+	DryRun   bool   `json:"dry-run,omitempty"`
+	Tail     bool   `json:"tail,omitempty"`
+	ID       string `json:"id,omitempty"`
+	ParentID string `json:"parent_id,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Current  int64  `json:"current,omitempty"`
+	Total    int64  `json:"total,omitempty"`
+	Percent  int    `json:"percent,omitempty"`
+}
+
+// event converts the message into a ProgressEvent, ok is false for messages that are not about a resource
+func (m progressMessage) event() (ProgressEvent, bool) {
+	if m.Tail || m.ID == "" {
+		return ProgressEvent{}, false
+	}
+	e := ProgressEvent{
+		ID:       m.ID,
+		ParentID: m.ParentID,
+		Text:     m.Text,
+		Detail:   m.Status,
+		Status:   ProgressStatusWorking,
+		Current:  m.Current,
+		Total:    m.Total,
+		Percent:  m.Percent,
+	}
+	switch {
+	case strings.EqualFold(m.Text, "Error"):
+		e.Status = ProgressStatusError
+	case strings.EqualFold(m.Text, "Warning"):
+		e.Status = ProgressStatusWarning
+	case slices.Contains(progressDoneTexts, m.Text):
+		e.Status = ProgressStatusDone
+	}
+	return e, true
+}
+
+// plain renders the message like docker compose --progress plain
+func (m progressMessage) plain() string {
+	if m.Error {
+		return m.Message
+	}
+	if m.Tail || m.ID == "" {
+		return m.Text
+	}
+	return strings.TrimSpace(fmt.Sprintf("%s %s %s", m.ID, m.Text, m.Status))
+}
+
+// reportProgress switches the command to json progress output and parses its stderr into progress events
+// for fn, while writer receives the progress as plain text. Lines that are not json are written as is.
+// The returned flush must be called once the command has finished.
+func reportProgress(cmd *Command, writer io.Writer, fn ProgressFunc) (flush func()) {
+	cmd.Args = setProgressFlag(cmd.Args, string(ProgressJSON))
+	w := &progressWriter{fn: fn}
+	if writer != nil {
+		// stdout is copied while the progress is written, both end up in writer
+		shared := newSyncWriter(writer)
+		cmd.Stdout = shared
+		w.writer = shared
+	}
+	cmd.Stderr = w
+	return w.flush
+}
+
+// progressFlag returns the value of --progress in the global flags, which precede "-f -"
+func progressFlag(args []string) string {
+	mode := ""
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-f" && args[i+1] == "-" {
+			break
+		}
+		if args[i] == "--progress" {
+			mode = args[i+1]
+		}
+	}
+	return mode
+}
+
+// plainProgress renders --progress json output as plain text, lines that are not json are kept as is
+func plainProgress(output string) string {
+	var b strings.Builder
+	for _, line := range strings.SplitAfter(output, "\n") {
+		if m, ok := parseProgressMessage([]byte(line)); ok {
+			line = m.plain() + "\n"
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// parseProgressMessage parses a line of --progress json output, ok is false when the line is not json
+func parseProgressMessage(line []byte) (m progressMessage, ok bool) {
+	trimmed := bytes.TrimSpace(line)
+	if len(trimmed) == 0 || trimmed[0] != '{' {
+		return m, false
+	}
+	return m, json.Unmarshal(trimmed, &m) == nil
+}
+
+// setProgressFlag sets --progress in the global flags, which precede "-f -"
+func setProgressFlag(args []string, mode string) []string {
+	end := len(args)
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-f" && args[i+1] == "-" {
+			end = i
+			break
+		}
+	}
+	out := make([]string, 0, len(args)+2)
+	for i := 0; i < end; i++ {
+		if args[i] == "--progress" && i+1 < end {
+			i++
+			continue
+		}
+		out = append(out, args[i])
+	}
+	out = append(out, "--progress", mode)
+	return append(out, args[end:]...)
+}
+
+type progressWriter struct {
+	writer io.Writer
+	fn     ProgressFunc
+	buffer bytes.Buffer
+}
+
+func (w *progressWriter) Write(p []byte) (n int, err error) {
+	n, err = w.buffer.Write(p)
+	if err != nil {
+		return n, err
+	}
+	for {
+		line, err := w.buffer.ReadBytes('\n')
+		if err == io.EOF {
+			// Incomplete line; keep in buffer
+			w.buffer.Write(line)
+			break
+		} else if err != nil {
+			return n, err
+		}
+		if err := w.handle(line); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// handle reports a single line and writes its plain text to the writer
+func (w *progressWriter) handle(line []byte) error {
+	text := string(bytes.TrimRight(line, "\r\n"))
+	if m, ok := parseProgressMessage(line); ok {
+		if e, ok := m.event(); ok {
+			w.fn(e)
+		}
+		text = m.plain()
+	}
+	if w.writer == nil {
+		return nil
+	}
+	_, err := io.WriteString(w.writer, text+"\n")
+	return err
+}
+
+func (w *progressWriter) flush() {
+	if w.buffer.Len() > 0 {
+		line := w.buffer.Bytes()
+		w.buffer.Reset()
+		w.handle(line)
+	}
+}
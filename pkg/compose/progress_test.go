@@ -0,0 +1,155 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressWriter(t *testing.T) {
+	data, err := os.ReadFile("testdata/progress.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	events := []ProgressEvent{}
+	out := &bytes.Buffer{}
+	w := &progressWriter{writer: out, fn: func(e ProgressEvent) { events = append(events, e) }}
+	// write in small chunks to exercise partial line buffering
+	for i := 0; i < len(data); i += 9 {
+		end := min(i+9, len(data))
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	w.flush()
+	assert.Equal(t, []ProgressEvent{
+		{ID: "web", Text: "Pulling", Status: ProgressStatusWorking},
+		{
+			ID:       "4abcf2066143",
+			ParentID: "web",
+			Text:     "Downloading",
+			Detail:   "[=====>          ]  1.2MB/3.6MB",
+			Status:   ProgressStatusWorking,
+			Current:  1200000,
+			Total:    3600000,
+			Percent:  33,
+		},
+		{ID: "4abcf2066143", ParentID: "web", Text: "Pull complete", Status: ProgressStatusDone},
+		{ID: "web", Text: "Pulled", Status: ProgressStatusDone},
+		{ID: "Network demo_default", Text: "Creating", Status: ProgressStatusWorking},
+		{ID: "Network demo_default", Text: "Created", Status: ProgressStatusDone},
+		{ID: "Container demo-web-1", Text: "Starting", Status: ProgressStatusWorking},
+		{ID: "Container demo-web-1", Text: "Error", Detail: "driver failed programming external connectivity", Status: ProgressStatusError},
+	}, events)
+	assert.True(t, events[3].IsDone())
+	assert.True(t, events[7].IsError())
+	assert.Equal(t, `web Pulling
+4abcf2066143 Downloading [=====>          ]  1.2MB/3.6MB
+4abcf2066143 Pull complete
+web Pulled
+Network demo_default Creating
+Network demo_default Created
+Container demo-web-1 Starting
+Container demo-web-1 Error driver failed programming external connectivity
+web exited with code 1
+Error response from daemon: driver failed programming external connectivity
+`, out.String())
+}
+
+func TestSetProgressFlag(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			args:     []string{"compose", "-f", "-", "up", "--detach"},
+			expected: []string{"compose", "--progress", "json", "-f", "-", "up", "--detach"},
+		},
+		{
+			args:     []string{"compose", "--progress", "plain", "--ansi", "never", "-f", "-", "pull"},
+			expected: []string{"compose", "--ansi", "never", "--progress", "json", "-f", "-", "pull"},
+		},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expected, setProgressFlag(tt.args, "json"))
+	}
+}
+
+func TestPullProgress(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		_, err := cmd.Stderr.Write([]byte("{\"id\":\"web\",\"text\":\"Pulling\"}\n{\"id\":\"web\",\"text\":\"Pulled\"}"))
+		return err
+	}
+	c := NewCompose(testProject(), WithRunner(runner), WithProgress(ProgressPlain))
+	events := []ProgressEvent{}
+	out := &bytes.Buffer{}
+	err := c.Pull(context.Background(), func(opt *ComposePullOptions) error {
+		opt.Writer = out
+		opt.Progress = func(e ProgressEvent) { events = append(events, e) }
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []ProgressEvent{
+		{ID: "web", Text: "Pulling", Status: ProgressStatusWorking},
+		{ID: "web", Text: "Pulled", Status: ProgressStatusDone},
+	}, events)
+	assert.Equal(t, "web Pulling\nweb Pulled\n", out.String())
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "--progress", "json", "-f", "-", "pull"}, cmd.Argv)
+}
+
+// TestProgressSharedWriter writes stdout and progress at the same time, run it with -race
+func TestProgressSharedWriter(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 100; i++ {
+				fmt.Fprintln(cmd.Stdout, "output")
+			}
+		}()
+		for i := 0; i < 100; i++ {
+			fmt.Fprintln(cmd.Stderr, `{"id":"web","text":"Working"}`)
+		}
+		<-done
+		return nil
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+	out := &bytes.Buffer{}
+	count := 0
+	progress := func(ProgressEvent) { count++ }
+	commands := map[string]func() error{
+		"up": func() error {
+			return c.Up(context.Background(), func(opt *ComposeUpOptions) error {
+				opt.Writer, opt.Progress = out, progress
+				return nil
+			})
+		},
+		"pull": func() error {
+			return c.Pull(context.Background(), func(opt *ComposePullOptions) error {
+				opt.Writer, opt.Progress = out, progress
+				return nil
+			})
+		},
+		"build": func() error {
+			return c.Build(context.Background(), func(opt *ComposeBuildOptions) error {
+				opt.Writer, opt.Progress = out, progress
+				return nil
+			})
+		},
+	}
+	for name, run := range commands {
+		out.Reset()
+		count = 0
+		assert.NoError(t, run(), name)
+		assert.Equal(t, 100, count, name)
+		assert.Equal(t, 100, strings.Count(out.String(), "output\n"), name)
+		assert.Equal(t, 100, strings.Count(out.String(), "web Working\n"), name)
+	}
+}
@@ -0,0 +1,126 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ServiceContainer is a container as reported by docker compose ps --format json
+type ServiceContainer struct {
+	ID         string      `json:"ID"`
+	Name       string      `json:"Name"`
+	Image      string      `json:"Image"`
+	Command    string      `json:"Command"`
+	Project    string      `json:"Project"`
+	Service    string      `json:"Service"`
+	State      string      `json:"State"`
+	Health     string      `json:"Health"`
+	ExitCode   int         `json:"ExitCode"`
+	Status     string      `json:"Status"`
+	CreatedAt  string      `json:"CreatedAt"`
+	Ports      string      `json:"Ports"`
+	Publishers []Publisher `json:"Publishers"`
+}
+
+// Publisher is a published port of a service container
+type Publisher struct {
+	URL           string `json:"URL"`
+	TargetPort    int    `json:"TargetPort"`
+	PublishedPort int    `json:"PublishedPort"`
+	Protocol      string `json:"Protocol"`
+}
+
+// IsRunning reports whether the container state is running
+func (s ServiceContainer) IsRunning() bool {
+	return s.State == "running"
+}
+
+// IsHealthy reports whether the container health is healthy
+func (s ServiceContainer) IsHealthy() bool {
+	return s.Health == "healthy"
+}
+
+// PsList runs the docker compose ps command with json output and returns the parsed containers.
+// The writer set with ps.WithWriter only receives stderr output.
+// ps.WithQuiet and ps.WithServices cannot be used with PsList.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) PsList(ctx context.Context, setters ...SetComposePsOption) ([]ServiceContainer, error) {
+	opt := &ComposePsOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposePsError(err)
+		}
+	}
+	if opt.Quiet {
+		return nil, NewComposePsError(NewComposeFlagError("--quiet", "WithQuiet cannot be used with PsList"))
+	}
+	if opt.Services {
+		return nil, NewComposePsError(NewComposeFlagError("--services", "WithServices cannot be used with PsList"))
+	}
+	opt.Format = "json"
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposePsError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, NewComposePsError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposePsError(err)
+	}
+	containers, err := parsePsOutput(stdout.Bytes())
+	if err != nil {
+		return nil, NewComposePsError(err)
+	}
+	return containers, nil
+}
+
+// parsePsOutput parses the json output of docker compose ps.
+// Older compose versions print a single json array, newer versions print one json object per line.
+func parsePsOutput(data []byte) ([]ServiceContainer, error) {
+	containers, err := decodeJSONList[ServiceContainer](data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ps output: %w", err)
+	}
+	return containers, nil
+}
+
+// decodeJSONList decodes either a single json array or one json object per line into a slice
+func decodeJSONList[T any](data []byte) ([]T, error) {
+	data = bytes.TrimSpace(data)
+	list := []T{}
+	if len(data) == 0 {
+		return list, nil
+	}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var item T
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,122 @@
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePsOutput(t *testing.T) {
+	tests := []struct {
+		fixture  string
+		message  string
+		expected []ServiceContainer
+	}{
+		{
+			fixture: "testdata/ps_lines.json",
+			message: "one json object per line",
+			expected: []ServiceContainer{
+				{
+					ID:        "4b1c2a6f0d3e",
+					Name:      "demo-web-1",
+					Image:     "nginx:alpine",
+					Command:   "\"/docker-entrypoint.…\"",
+					Project:   "demo",
+					Service:   "web",
+					State:     "running",
+					Status:    "Up 2 minutes",
+					CreatedAt: "2025-06-01 10:12:44 +0000 UTC",
+					Ports:     "0.0.0.0:9080->80/tcp, :::9080->80/tcp",
+					Publishers: []Publisher{
+						{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 9080, Protocol: "tcp"},
+						{URL: "::", TargetPort: 80, PublishedPort: 9080, Protocol: "tcp"},
+					},
+				},
+				{
+					ID:         "9e8f7d6c5b4a",
+					Name:       "demo-db-1",
+					Image:      "postgres:16",
+					Command:    "\"docker-entrypoint.s…\"",
+					Project:    "demo",
+					Service:    "db",
+					State:      "running",
+					Health:     "healthy",
+					Status:     "Up 2 minutes (healthy)",
+					CreatedAt:  "2025-06-01 10:12:43 +0000 UTC",
+					Ports:      "5432/tcp",
+					Publishers: []Publisher{{TargetPort: 5432, Protocol: "tcp"}},
+				},
+				{
+					ID:        "1a2b3c4d5e6f",
+					Name:      "demo-job-1",
+					Image:     "alpine:latest",
+					Command:   "\"sh -c 'exit 3'\"",
+					Project:   "demo",
+					Service:   "job",
+					State:     "exited",
+					ExitCode:  3,
+					Status:    "Exited (3) 1 minute ago",
+					CreatedAt: "2025-06-01 10:12:45 +0000 UTC",
+				},
+			},
+		},
+		{
+			fixture: "testdata/ps_array.json",
+			message: "single json array",
+			expected: []ServiceContainer{
+				{
+					ID:         "4b1c2a6f0d3e",
+					Name:       "demo-web-1",
+					Image:      "nginx:alpine",
+					Command:    "/docker-entrypoint.sh nginx -g 'daemon off;'",
+					Project:    "demo",
+					Service:    "web",
+					State:      "running",
+					Status:     "Up 2 minutes",
+					Publishers: []Publisher{{URL: "0.0.0.0", TargetPort: 80, PublishedPort: 9080, Protocol: "tcp"}},
+				},
+				{
+					ID:      "9e8f7d6c5b4a",
+					Name:    "demo-db-1",
+					Image:   "postgres:16",
+					Command: "docker-entrypoint.sh postgres",
+					Project: "demo",
+					Service: "db",
+					State:   "running",
+					Health:  "healthy",
+					Status:  "Up 2 minutes (healthy)",
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.fixture)
+		if !assert.NoError(t, err, tt.message) {
+			continue
+		}
+		containers, err := parsePsOutput(data)
+		assert.NoError(t, err, tt.message)
+		assert.Equal(t, tt.expected, containers, tt.message)
+	}
+}
+
+func TestParsePsOutputEmpty(t *testing.T) {
+	containers, err := parsePsOutput([]byte("\n"))
+	assert.NoError(t, err)
+	assert.Empty(t, containers)
+}
+
+func TestParsePsOutputInvalid(t *testing.T) {
+	_, err := parsePsOutput([]byte("NAME  IMAGE  SERVICE\n"))
+	assert.Error(t, err)
+}
+
+func TestServiceContainerState(t *testing.T) {
+	c := ServiceContainer{State: "running", Health: "healthy"}
+	assert.True(t, c.IsRunning())
+	assert.True(t, c.IsHealthy())
+	c = ServiceContainer{State: "exited", ExitCode: 1}
+	assert.False(t, c.IsRunning())
+	assert.False(t, c.IsHealthy())
+}
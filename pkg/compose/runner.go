@@ -0,0 +1,330 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Command is a single compose invocation handed to a CommandRunner
+type Command struct {
+	// Name is the binary to execute, e.g. "docker"
+	Name string
+	// Args are the arguments passed to the binary, e.g. ["compose", "-f", "-", "up", "--detach"]
+	Args []string
+	// Env is the environment of the process, nil inherits the environment of the current process
+	Env []string
+	// Dir is the working directory of the process, empty uses the current working directory
+	Dir string
+	// Stdin provides the compose file followed by any user supplied input
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// GracePeriod is how long to wait for the process to exit after it was interrupted
+	// because the context was done, before it is killed. Zero kills the process immediately.
+	GracePeriod time.Duration
+
+	// subcommand is the compose subcommand, e.g. "up", used to report interrupted commands
+	subcommand string
+	// file is the rendered compose yaml at the start of stdin, nil for commands that do not operate on the project
+	file []byte
+}
+
+// Argv returns the full command line including the binary name
+func (c *Command) Argv() []string {
+	return append([]string{c.Name}, c.Args...)
+}
+
+// CommandRunner runs compose commands.
+// Implementations must block until the command has finished and honor context cancellation.
+// Errors carrying a process exit code should implement `ExitCode() int` like *exec.ExitError.
+type CommandRunner interface {
+	Run(ctx context.Context, cmd *Command) error
+}
+
+// execRunner is the default CommandRunner, it executes commands with os/exec
+type execRunner struct{}
+
+func (r *execRunner) Run(ctx context.Context, cmd *Command) error {
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	c.Env = cmd.Env
+	c.Dir = cmd.Dir
+	c.Stdin = cmd.Stdin
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+	if cmd.GracePeriod > 0 {
+		// interrupt like ctrl+c so compose can clean up, exec kills the process once WaitDelay has passed
+		c.Cancel = func() error {
+			if err := c.Process.Signal(os.Interrupt); err != nil && !errors.Is(err, os.ErrProcessDone) {
+				// interrupt is not supported on windows
+				return c.Process.Kill()
+			}
+			return nil
+		}
+		c.WaitDelay = cmd.GracePeriod
+	}
+	return c.Run()
+}
+
+// maxStderrCapture is the number of trailing stderr bytes kept for CommandError
+const maxStderrCapture = 64 * 1024
+
+// run executes the command with the configured runner while capturing the tail of its stderr output.
+// The output is still written to the stderr writer of the command.
+// A failed command is returned as a CommandError classifying the captured output.
+// In dry run mode the plan of the command is reported, see WithDryRun and WithCLIDryRun.
+func (c *compose) run(ctx context.Context, cmd *Command) error {
+	serializeOutput(cmd)
+	if c.dryRun == nil {
+		return c.exec(ctx, cmd)
+	}
+	plan := c.plan(cmd)
+	if !c.dryRunCLI {
+		c.dryRun(plan)
+		return nil
+	}
+	actions := newDryRunWriter()
+	cmd.Stdout = teeWriter(cmd.Stdout, actions.stream(LogStreamStdout))
+	cmd.Stderr = teeWriter(cmd.Stderr, actions.stream(LogStreamStderr))
+	err := c.exec(ctx, cmd)
+	plan.Actions = actions.result()
+	c.dryRun(plan)
+	return err
+}
+
+// exec executes the command with the configured runner
+func (c *compose) exec(ctx context.Context, cmd *Command) error {
+	serializeOutput(cmd)
+	stderr := &tailBuffer{max: maxStderrCapture}
+	if cmd.Stderr != nil {
+		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
+	} else {
+		cmd.Stderr = stderr
+	}
+	err := c.runner.Run(ctx, cmd)
+	if err == nil {
+		return nil
+	}
+	output := stderr.String()
+	if progressFlag(cmd.Args) == string(ProgressJSON) {
+		// the error messages are fields of the json events, classify them like plain output
+		output = plainProgress(output)
+	}
+	cmdErr := NewCommandError(cmd, err, output)
+	if c.contextErrors {
+		switch ctx.Err() {
+		case context.Canceled:
+			return NewComposeCanceledError(cmd.subcommand, cmdErr)
+		case context.DeadlineExceeded:
+			return NewComposeDeadlineExceededError(cmd.subcommand, cmdErr)
+		}
+	}
+	return cmdErr
+}
+
+// serializeOutput wraps stdout and stderr of the command in a single syncWriter when they are the same writer.
+// The streams are copied concurrently, a shared writer such as a bytes.Buffer would otherwise be written from two goroutines.
+func serializeOutput(cmd *Command) {
+	if !sameWriter(cmd.Stdout, cmd.Stderr) {
+		return
+	}
+	w := newSyncWriter(cmd.Stdout)
+	cmd.Stdout = w
+	cmd.Stderr = w
+}
+
+// sameWriter reports whether a and b are the same non nil writer
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	// comparing interfaces holding the same uncomparable type panics
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// syncWriter is an io.Writer that serializes writes to the underlying writer
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// newSyncWriter wraps w in a syncWriter, w is returned as is when it already is one
+func newSyncWriter(w io.Writer) *syncWriter {
+	if s, ok := w.(*syncWriter); ok {
+		return s
+	}
+	return &syncWriter{w: w}
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
+
+// tailBuffer is an io.Writer that keeps the last max bytes written to it, it is safe for concurrent use
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	if over := len(b.buf) - b.max; over > 0 {
+		b.buf = append(b.buf[:0], b.buf[over:]...)
+	}
+	return len(p), nil
+}
+
+func (b *tailBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.buf)
+}
+
+// WithBinary sets the binary and the arguments that precede every compose subcommand.
+//
+// Eg.
+//
+//	WithBinary("docker", "compose")   // default
+//	WithBinary("docker-compose")      // compose v1 standalone
+//	WithBinary("podman", "compose")
+//	WithBinary("/usr/local/bin/nerdctl", "compose")
+func WithBinary(name string, args ...string) SetComposeOption {
+	return func(c *compose) error {
+		if name == "" {
+			return NewComposeError(fmt.Errorf("WithBinary: binary name is required"))
+		}
+		c.binary = name
+		c.base = args
+		return nil
+	}
+}
+
+// DefaultGracePeriod is the default time a compose process is given to exit after it was interrupted
+const DefaultGracePeriod = 10 * time.Second
+
+// WithGracePeriod sets how long a compose process is given to exit when the context of a command is done.
+// The process is sent SIGINT first, like pressing ctrl+c, and killed when it is still running after the grace period.
+// A grace period of 0 kills the process immediately (default: DefaultGracePeriod).
+func WithGracePeriod(period time.Duration) SetComposeOption {
+	return func(c *compose) error {
+		if period < 0 {
+			return NewComposeError(fmt.Errorf("WithGracePeriod: grace period must not be negative"))
+		}
+		c.gracePeriod = period
+		return nil
+	}
+}
+
+// WithContextErrors makes commands return an error when they are stopped because their context was canceled
+// or its deadline exceeded, instead of treating it as success.
+// The errors are a ComposeCanceledError or ComposeDeadlineExceededError naming the interrupted command,
+// they also match context.Canceled and context.DeadlineExceeded with errors.Is.
+// Streaming commands such as Events and Stats send the error on their error channel.
+// Commands returning parsed output such as PsList always return these errors, their output would be incomplete.
+func WithContextErrors() SetComposeOption {
+	return func(c *compose) error {
+		c.contextErrors = true
+		return nil
+	}
+}
+
+// WithRunner sets the CommandRunner used to execute compose commands (default: os/exec)
+func WithRunner(runner CommandRunner) SetComposeOption {
+	return func(c *compose) error {
+		if runner == nil {
+			return NewComposeError(fmt.Errorf("WithRunner: runner is nil"))
+		}
+		c.runner = runner
+		return nil
+	}
+}
+
+// RecordedCommand is a command captured by a RecordingRunner
+type RecordedCommand struct {
+	// Argv is the full command line including the binary name
+	Argv []string
+	// Stdin is everything that would have been written to stdin, starting with the rendered compose yaml
+	Stdin []byte
+	Env   []string
+	Dir   string
+}
+
+// RecordingRunner is a CommandRunner that records commands instead of executing them.
+// It is meant for tests that assert exactly what would have been executed.
+//
+// note: the stdin of every command is read to the end, do not pass an interactive reader such as os.Stdin.
+type RecordingRunner struct {
+	// Respond is called for every command after it has been recorded, when set.
+	// It may write to cmd.Stdout or cmd.Stderr to script output, the returned error is returned by Run.
+	Respond func(ctx context.Context, cmd *Command) error
+
+	mu       sync.Mutex
+	commands []RecordedCommand
+}
+
+// NewRecordingRunner creates a new RecordingRunner
+func NewRecordingRunner() *RecordingRunner {
+	return &RecordingRunner{}
+}
+
+func (r *RecordingRunner) Run(ctx context.Context, cmd *Command) error {
+	recorded := RecordedCommand{
+		Argv: cmd.Argv(),
+		Env:  append([]string(nil), cmd.Env...),
+		Dir:  cmd.Dir,
+	}
+	if cmd.Stdin != nil {
+		stdin, err := io.ReadAll(cmd.Stdin)
+		if err != nil {
+			return err
+		}
+		recorded.Stdin = stdin
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	r.mu.Lock()
+	r.commands = append(r.commands, recorded)
+	r.mu.Unlock()
+	if r.Respond != nil {
+		return r.Respond(ctx, cmd)
+	}
+	return nil
+}
+
+// Commands returns a copy of all recorded commands in the order they were run
+func (r *RecordingRunner) Commands() []RecordedCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]RecordedCommand(nil), r.commands...)
+}
+
+// Last returns the most recently recorded command, false if nothing was recorded
+func (r *RecordingRunner) Last() (RecordedCommand, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.commands) == 0 {
+		return RecordedCommand{}, false
+	}
+	return r.commands[len(r.commands)-1], true
+}
+
+// Reset clears all recorded commands
+func (r *RecordingRunner) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = nil
+}
@@ -0,0 +1,331 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProject() *create.Project {
+	project := create.NewProject("demo")
+	project.WithService("web", create.NewContainer("web").WithContainerConfig(cc.WithImage("nginx:alpine")))
+	return project
+}
+
+type testExitError struct{ code int }
+
+func (e *testExitError) Error() string { return fmt.Sprintf("exit status %d", e.code) }
+func (e *testExitError) ExitCode() int { return e.code }
+
+func TestRecordingRunner(t *testing.T) {
+	runner := NewRecordingRunner()
+	c := NewCompose(testProject(), WithRunner(runner))
+	err := c.Up(context.Background(), func(opt *ComposeUpOptions) error {
+		opt.Detach = true
+		return nil
+	})
+	assert.NoError(t, err)
+	cmd, ok := runner.Last()
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "up", "--detach"}, cmd.Argv)
+	assert.Contains(t, string(cmd.Stdin), "image: nginx:alpine")
+	assert.Nil(t, cmd.Env)
+
+	runner.Reset()
+	assert.Empty(t, runner.Commands())
+}
+
+func TestWithBinary(t *testing.T) {
+	runner := NewRecordingRunner()
+	c := NewCompose(testProject(), WithBinary("podman", "compose"), WithRunner(runner))
+	assert.NoError(t, c.Down(context.Background()))
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"podman", "compose", "-f", "-", "down"}, cmd.Argv)
+
+	c = NewCompose(testProject(), WithBinary("docker-compose"), WithRunner(runner))
+	_, err := c.Ls(context.Background())
+	assert.NoError(t, err)
+	cmd, _ = runner.Last()
+	assert.Equal(t, []string{"docker-compose", "ls", "--format", "json"}, cmd.Argv)
+
+	c = NewCompose(testProject(), WithBinary(""), WithRunner(runner))
+	assert.True(t, IsComposeDownError(c.Down(context.Background())))
+}
+
+func TestRecordingRunnerRespond(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		switch cmd.Args[3] {
+		case "ps":
+			_, err := fmt.Fprintln(cmd.Stdout, `{"Name":"demo-web-1","Service":"web","State":"running"}`)
+			return err
+		case "run":
+			return &testExitError{code: 3}
+		}
+		return errors.New("unexpected command " + strings.Join(cmd.Args, " "))
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+
+	containers, err := c.PsList(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []ServiceContainer{{Name: "demo-web-1", Service: "web", State: "running"}}, containers)
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "ps", "--format", "json"}, cmd.Argv)
+
+	err = c.Run(context.Background(), func(opt *ComposeRunOptions) error {
+		opt.Service = "web"
+		return nil
+	})
+	var runErr *ComposeRunError
+	if assert.True(t, errors.As(err, &runErr)) {
+		assert.Equal(t, 3, runErr.ExitCode)
+	}
+}
+
+func TestContextErrors(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancelExpired()
+
+	c := NewCompose(testProject(), WithRunner(runner))
+	assert.NoError(t, c.Up(canceled))
+	assert.NoError(t, c.Up(expired))
+
+	c = NewCompose(testProject(), WithRunner(runner), WithContextErrors())
+	err := c.Up(canceled)
+	assert.True(t, IsComposeUpError(err))
+	assert.True(t, IsComposeCanceledError(err))
+	assert.ErrorIs(t, err, context.Canceled)
+	var canceledErr *ComposeCanceledError
+	if assert.ErrorAs(t, err, &canceledErr) {
+		assert.Equal(t, "up", canceledErr.Command)
+	}
+
+	err = c.Stop(expired)
+	assert.True(t, IsComposeStopError(err))
+	assert.True(t, IsComposeDeadlineExceededError(err))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var deadlineErr *ComposeDeadlineExceededError
+	if assert.ErrorAs(t, err, &deadlineErr) {
+		assert.Equal(t, "stop", deadlineErr.Command)
+	}
+
+	c = NewCompose(testProject(), WithRunner(runner), WithGracePeriod(-time.Second))
+	assert.True(t, IsComposeUpError(c.Up(context.Background())))
+}
+
+// TestQueryContextErrors checks that commands returning parsed output fail when they are stopped by the context,
+// even without WithContextErrors
+func TestQueryContextErrors(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+	queries := map[string]func(ctx context.Context) error{
+		"ps": func(ctx context.Context) error {
+			_, err := c.PsList(ctx)
+			return err
+		},
+		"port": func(ctx context.Context) error {
+			_, err := c.Port(ctx, func(opt *ComposePortOptions) error {
+				opt.Service = "web"
+				opt.PrivatePort = 80
+				return nil
+			})
+			return err
+		},
+		"top": func(ctx context.Context) error {
+			_, err := c.Top(ctx)
+			return err
+		},
+		"ls": func(ctx context.Context) error {
+			_, err := c.Ls(ctx)
+			return err
+		},
+		"images": func(ctx context.Context) error {
+			_, err := c.Images(ctx)
+			return err
+		},
+		"config": func(ctx context.Context) error {
+			_, err := c.Config(ctx)
+			return err
+		},
+	}
+	for name, query := range queries {
+		canceled, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := query(canceled)
+		assert.True(t, IsComposeCanceledError(err), name)
+		assert.ErrorIs(t, err, context.Canceled, name)
+		var canceledErr *ComposeCanceledError
+		if assert.ErrorAs(t, err, &canceledErr, name) {
+			assert.Equal(t, name, canceledErr.Command)
+		}
+
+		expired, cancelExpired := context.WithTimeout(context.Background(), time.Millisecond)
+		err = query(expired)
+		cancelExpired()
+		assert.True(t, IsComposeDeadlineExceededError(err), name)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, name)
+	}
+}
+
+func TestExecRunnerGracePeriod(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("interrupt is not supported on windows")
+	}
+	tests := []struct {
+		script   string
+		period   time.Duration
+		message  string
+		expected string
+	}{
+		{
+			script:   `trap 'echo interrupted; exit 0' INT; echo ready; while :; do sleep 0.05; done`,
+			period:   5 * time.Second,
+			message:  "process handles the interrupt",
+			expected: "ready\ninterrupted\n",
+		},
+		{
+			script:   `trap '' INT; echo ready; while :; do sleep 0.05; done`,
+			period:   200 * time.Millisecond,
+			message:  "process ignoring the interrupt is killed after the grace period",
+			expected: "ready\n",
+		},
+	}
+	for _, tt := range tests {
+		ctx, cancel := context.WithCancel(context.Background())
+		out := &syncBuffer{}
+		cmd := &Command{Name: "sh", Args: []string{"-c", tt.script}, Stdout: out, GracePeriod: tt.period}
+		done := make(chan error, 1)
+		go func() { done <- (&execRunner{}).Run(ctx, cmd) }()
+		for !strings.Contains(out.String(), "ready") {
+			time.Sleep(10 * time.Millisecond)
+		}
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			t.Fatal(tt.message + ": process was not stopped")
+		}
+		assert.Equal(t, tt.expected, out.String(), tt.message)
+	}
+}
+
+// writeConcurrently responds to every command by writing n lines to stdout and stderr at the same time,
+// like os/exec copying both pipes of a process
+func writeConcurrently(n int, line string) func(ctx context.Context, cmd *Command) error {
+	return func(ctx context.Context, cmd *Command) error {
+		var wg sync.WaitGroup
+		for _, w := range []io.Writer{cmd.Stdout, cmd.Stderr} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < n; i++ {
+					fmt.Fprintln(w, line)
+				}
+			}()
+		}
+		wg.Wait()
+		return nil
+	}
+}
+
+// TestSharedWriter runs commands whose stdout and stderr are the same writer, run it with -race
+func TestSharedWriter(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = writeConcurrently(100, "output")
+	out := &bytes.Buffer{}
+	withWriter := func(opt *ComposeUpOptions) error {
+		opt.Writer = out
+		return nil
+	}
+
+	c := NewCompose(testProject(), WithRunner(runner))
+	assert.NoError(t, c.Up(context.Background(), withWriter))
+	assert.Equal(t, 200, strings.Count(out.String(), "output\n"))
+
+	out.Reset()
+	c = NewCompose(testProject(), WithRunner(runner), WithCLIDryRun(func(DryRunPlan) {}))
+	assert.NoError(t, c.Up(context.Background(), withWriter))
+	assert.Equal(t, 200, strings.Count(out.String(), "output\n"))
+
+	runner.Respond = writeConcurrently(100, `{"time":"2024-01-01T00:00:00Z","type":"container","action":"start","id":"1","service":"web"}`)
+	c = NewCompose(testProject(), WithRunner(runner))
+	events, errs, err := c.Events(context.Background(), "")
+	if !assert.NoError(t, err) {
+		return
+	}
+	count := 0
+	for range events {
+		count++
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 200, count)
+
+	out.Reset()
+	runner.Respond = writeConcurrently(100, "Watch enabled")
+	watchEvents, errs, err := c.Watch(context.Background(), func(opt *ComposeWatchOptions) error {
+		opt.Writer = out
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	count = 0
+	for range watchEvents {
+		count++
+	}
+	assert.NoError(t, <-errs)
+	assert.Equal(t, 200, count)
+	assert.Equal(t, 200, strings.Count(out.String(), "Watch enabled\n"))
+}
+
+func TestSameWriter(t *testing.T) {
+	a, b := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.True(t, sameWriter(a, a))
+	assert.False(t, sameWriter(a, b))
+	assert.False(t, sameWriter(a, nil))
+	assert.False(t, sameWriter(nil, nil))
+	// uncomparable writers are never the same
+	assert.False(t, sameWriter(writerFunc(a.Write), writerFunc(a.Write)))
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
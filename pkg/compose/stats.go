@@ -0,0 +1,205 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/go-units"
+)
+
+// StatsSample is a resource usage sample of a single container as reported by docker compose stats
+type StatsSample struct {
+	ID   string
+	Name string
+	// CPUPercent is the cpu usage in percent, may exceed 100 on multi core hosts
+	CPUPercent float64
+	// MemUsage and MemLimit are in bytes
+	MemUsage   int64
+	MemLimit   int64
+	MemPercent float64
+	// NetInput and NetOutput are the received and sent network bytes
+	NetInput  int64
+	NetOutput int64
+	// BlockInput and BlockOutput are the read and written block device bytes
+	BlockInput  int64
+	BlockOutput int64
+	PIDs        int
+}
+
+// statsLine is the raw json line printed by docker compose stats --format json
+type statsLine struct {
+	ID        string `json:"ID"`
+	Name      string `json:"Name"`
+	CPUPerc   string `json:"CPUPerc"`
+	MemUsage  string `json:"MemUsage"`
+	MemPerc   string `json:"MemPerc"`
+	NetIO     string `json:"NetIO"`
+	BlockIO   string `json:"BlockIO"`
+	PIDs      string `json:"PIDs"`
+	Container string `json:"Container"`
+}
+
+// Stats runs the docker compose stats command and streams parsed samples on the returned channel.
+// Both channels are closed when the command exits; with stats.WithNoStream it exits after one sample per container.
+// The writer set with stats.WithWriter only receives stderr output.
+func (c *compose) Stats(ctx context.Context, setters ...SetComposeStatsOption) (<-chan StatsSample, <-chan error, error) {
+	opt := &ComposeStatsOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, nil, NewComposeStatsError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, nil, NewComposeStatsError(err)
+	}
+	if opt.Service != "" {
+		flags = append(flags, opt.Service)
+	}
+	statsCh := make(chan StatsSample, 1)
+	errCh := make(chan error, 1)
+
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, nil, NewComposeStatsError(err)
+	}
+	cmd.Stdout = newStatsWriter(ctx, statsCh, errCh)
+
+	go func() {
+		defer close(statsCh)
+		defer close(errCh)
+
+		if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+			errCh <- NewComposeStatsError(err)
+		}
+	}()
+
+	return statsCh, errCh, nil
+}
+
+type statsWriter struct {
+	ctx    context.Context
+	ch     chan StatsSample
+	errCh  chan error
+	buffer bytes.Buffer
+}
+
+func (w *statsWriter) Write(p []byte) (n int, err error) {
+	n, err = w.buffer.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	for {
+		line, err := w.buffer.ReadBytes('\n')
+		if err == io.EOF {
+			// Incomplete line; keep in buffer
+			w.buffer.Write(line)
+			break
+		} else if err != nil {
+			return n, err
+		}
+		// streaming output clears the screen with ansi escapes before each refresh
+		start := bytes.IndexByte(line, '{')
+		if start == -1 {
+			continue
+		}
+		sample, err := parseStatsLine(line[start:])
+		if err == nil {
+			select {
+			case <-w.ctx.Done():
+				w.buffer.Reset()
+				return n, w.ctx.Err()
+			case w.ch <- *sample:
+			}
+		} else {
+			select {
+			case <-w.ctx.Done():
+				w.buffer.Reset()
+				return n, w.ctx.Err()
+			case w.errCh <- NewComposeStatsError(err):
+			}
+		}
+	}
+
+	return n, nil
+}
+
+func newStatsWriter(ctx context.Context, ch chan StatsSample, errCh chan error) io.Writer {
+	return &statsWriter{ctx: ctx, ch: ch, errCh: errCh}
+}
+
+// parseStatsLine parses a single json line of docker compose stats --format json
+func parseStatsLine(line []byte) (*StatsSample, error) {
+	var raw statsLine
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse stats output: %w", err)
+	}
+	sample := &StatsSample{ID: raw.ID, Name: raw.Name}
+	if sample.ID == "" {
+		sample.ID = raw.Container
+	}
+	var err error
+	if sample.CPUPercent, err = parsePercent(raw.CPUPerc); err != nil {
+		return nil, err
+	}
+	if sample.MemPercent, err = parsePercent(raw.MemPerc); err != nil {
+		return nil, err
+	}
+	// memory is printed in binary units (MiB), network and block io in decimal units (kB)
+	if sample.MemUsage, sample.MemLimit, err = parseIOPair(raw.MemUsage, units.RAMInBytes); err != nil {
+		return nil, err
+	}
+	if sample.NetInput, sample.NetOutput, err = parseIOPair(raw.NetIO, units.FromHumanSize); err != nil {
+		return nil, err
+	}
+	if sample.BlockInput, sample.BlockOutput, err = parseIOPair(raw.BlockIO, units.FromHumanSize); err != nil {
+		return nil, err
+	}
+	if raw.PIDs != "" && raw.PIDs != "--" {
+		if sample.PIDs, err = strconv.Atoi(raw.PIDs); err != nil {
+			return nil, fmt.Errorf("failed to parse stats pids %q: %w", raw.PIDs, err)
+		}
+	}
+	return sample, nil
+}
+
+// parsePercent parses a value like "12.34%", "--" (container not running) is parsed as 0
+func parsePercent(value string) (float64, error) {
+	value = strings.TrimSuffix(strings.TrimSpace(value), "%")
+	if value == "" || value == "--" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse stats percentage %q: %w", value, err)
+	}
+	return f, nil
+}
+
+// parseIOPair parses a value like "1.2kB / 3.4MB", "-- / --" (container not running) is parsed as 0
+func parseIOPair(value string, parse func(string) (int64, error)) (int64, int64, error) {
+	left, right, ok := strings.Cut(value, "/")
+	if !ok {
+		return 0, 0, nil
+	}
+	values := [2]int64{}
+	for i, part := range []string{left, right} {
+		part = strings.TrimSpace(part)
+		if part == "" || part == "--" {
+			continue
+		}
+		v, err := parse(part)
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to parse stats size %q: %w", part, err)
+		}
+		values[i] = v
+	}
+	return values[0], values[1], nil
+}
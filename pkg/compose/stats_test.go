@@ -0,0 +1,70 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsWriter(t *testing.T) {
+	data, err := os.ReadFile("testdata/stats.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ch := make(chan StatsSample, 10)
+	errCh := make(chan error, 10)
+	w := newStatsWriter(context.Background(), ch, errCh)
+	// write in small chunks to exercise partial line buffering
+	for i := 0; i < len(data); i += 7 {
+		end := min(i+7, len(data))
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	close(ch)
+	close(errCh)
+	for err := range errCh {
+		assert.NoError(t, err)
+	}
+	samples := []StatsSample{}
+	for s := range ch {
+		samples = append(samples, s)
+	}
+	assert.Equal(t, []StatsSample{
+		{
+			ID:          "4b1c2a6f0d3e",
+			Name:        "demo-web-1",
+			CPUPercent:  0.25,
+			MemUsage:    9961472,
+			MemLimit:    8160437862,
+			MemPercent:  0.12,
+			NetInput:    1200,
+			NetOutput:   656,
+			BlockInput:  0,
+			BlockOutput: 4100,
+			PIDs:        3,
+		},
+		{
+			ID:          "9e8f7d6c5b4a",
+			Name:        "demo-db-1",
+			CPUPercent:  150.04,
+			MemUsage:    122683392,
+			MemLimit:    8160437862,
+			MemPercent:  1.5,
+			NetInput:    3400000,
+			NetOutput:   2100000,
+			BlockInput:  12300000,
+			BlockOutput: 45100000,
+			PIDs:        12,
+		},
+		{ID: "1a2b3c4d5e6f", Name: "demo-job-1"},
+	}, samples)
+}
+
+func TestParseStatsLineInvalid(t *testing.T) {
+	_, err := parseStatsLine([]byte(`{"CPUPerc":"abc%"}`))
+	assert.Error(t, err)
+	_, err = parseStatsLine([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package compose
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ContainerTop is the process table of a single service container as reported by docker compose top
+type ContainerTop struct {
+	// Container is the container name, only set by compose versions that print one table per container
+	Container string
+	// Service and Index are only set by compose versions that print a single table with SERVICE and # columns
+	Service string
+	Index   int
+	// Titles are the column headers of the process table (e.g. UID, PID, PPID, C, STIME, TTY, TIME, CMD)
+	Titles []string
+	// Processes are the rows of the process table, each row has one value per title
+	Processes [][]string
+}
+
+// Top runs the docker compose top command and returns the parsed process tables.
+// The writer set with top.WithWriter only receives stderr output.
+// A command stopped by the context returns a ComposeCanceledError or ComposeDeadlineExceededError.
+func (c *compose) Top(ctx context.Context, setters ...SetComposeTopOption) ([]ContainerTop, error) {
+	opt := &ComposeTopOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, NewComposeTopError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, NewComposeTopError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	cmd, err := c.command(ctx, opt.Writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, NewComposeTopError(err)
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	if err := handleQueryCancellation(ctx, cmd, c.run(ctx, cmd)); err != nil {
+		return nil, NewComposeTopError(err)
+	}
+	return parseTopOutput(stdout.Bytes()), nil
+}
+
+// parseTopOutput parses the text output of docker compose top.
+//
+// Older compose versions print the container name followed by a process table for each container,
+// separated by blank lines. Newer versions print a single table whose first columns are SERVICE and #.
+func parseTopOutput(data []byte) []ContainerTop {
+	tops := []ContainerTop{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var (
+		current *ContainerTop
+		titles  []string
+		merged  bool
+		index   = map[string]int{}
+	)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \r")
+		if strings.TrimSpace(line) == "" {
+			if current != nil {
+				tops = append(tops, *current)
+				current = nil
+			}
+			titles = nil
+			continue
+		}
+		fields := strings.Fields(line)
+		switch {
+		case titles == nil && len(fields) > 2 && fields[0] == "SERVICE" && fields[1] == "#":
+			titles = fields[2:]
+			merged = true
+		case titles == nil && current == nil:
+			current = &ContainerTop{Container: strings.TrimSpace(line)}
+			merged = false
+		case titles == nil:
+			titles = fields
+			current.Titles = titles
+		case merged:
+			row := splitColumns(line, len(titles)+2)
+			if len(row) < 2 {
+				continue
+			}
+			key := row[0] + "#" + row[1]
+			i, ok := index[key]
+			if !ok {
+				num, _ := strconv.Atoi(row[1])
+				tops = append(tops, ContainerTop{Service: row[0], Index: num, Titles: titles})
+				i = len(tops) - 1
+				index[key] = i
+			}
+			tops[i].Processes = append(tops[i].Processes, row[2:])
+		default:
+			current.Processes = append(current.Processes, splitColumns(line, len(titles)))
+		}
+	}
+	if current != nil {
+		tops = append(tops, *current)
+	}
+	return tops
+}
+
+// splitColumns splits a whitespace aligned table row into at most n columns,
+// the last column keeps the remainder of the line (e.g. a CMD containing spaces)
+func splitColumns(line string, n int) []string {
+	columns := make([]string, 0, n)
+	rest := strings.TrimSpace(line)
+	for len(columns) < n-1 && rest != "" {
+		i := strings.IndexAny(rest, " \t")
+		if i == -1 {
+			break
+		}
+		columns = append(columns, rest[:i])
+		rest = strings.TrimLeft(rest[i:], " \t")
+	}
+	if rest != "" {
+		columns = append(columns, rest)
+	}
+	return columns
+}
@@ -0,0 +1,72 @@
+package compose
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTopOutput(t *testing.T) {
+	titles := []string{"UID", "PID", "PPID", "C", "STIME", "TTY", "TIME", "CMD"}
+	master := []string{"root", "12345", "12320", "0", "10:12", "?", "00:00:00", "nginx: master process nginx -g daemon off;"}
+	worker := []string{"101", "12400", "12345", "0", "10:12", "?", "00:00:00", "nginx: worker process"}
+	postgres := []string{"999", "13001", "12980", "0", "10:12", "?", "00:00:01", "postgres"}
+	tests := []struct {
+		fixture  string
+		message  string
+		expected []ContainerTop
+	}{
+		{
+			fixture: "testdata/top_tables.txt",
+			message: "one table per container",
+			expected: []ContainerTop{
+				{Container: "demo-web-1", Titles: titles, Processes: [][]string{master, worker}},
+				{Container: "demo-db-1", Titles: titles, Processes: [][]string{postgres}},
+			},
+		},
+		{
+			fixture: "testdata/top_merged.txt",
+			message: "single table with service and index columns",
+			expected: []ContainerTop{
+				{Service: "web", Index: 1, Titles: titles, Processes: [][]string{master, worker}},
+				{Service: "web", Index: 2, Titles: titles, Processes: [][]string{
+					{"root", "12501", "12480", "0", "10:13", "?", "00:00:00", "nginx: master process nginx -g daemon off;"},
+				}},
+				{Service: "db", Index: 1, Titles: titles, Processes: [][]string{postgres}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		data, err := os.ReadFile(tt.fixture)
+		if !assert.NoError(t, err, tt.message) {
+			continue
+		}
+		assert.Equal(t, tt.expected, parseTopOutput(data), tt.message)
+	}
+}
+
+func TestParsePortOutput(t *testing.T) {
+	tests := []struct {
+		output   string
+		message  string
+		wantErr  bool
+		expected *PortBinding
+	}{
+		{output: "0.0.0.0:9080\n", message: "ipv4", expected: &PortBinding{HostIP: "0.0.0.0", HostPort: 9080}},
+		{output: "[::]:9080\n", message: "ipv6", expected: &PortBinding{HostIP: "::", HostPort: 9080}},
+		{output: "0.0.0.0:9080\n[::]:9080\n", message: "multiple bindings", expected: &PortBinding{HostIP: "0.0.0.0", HostPort: 9080}},
+		{output: "\n", message: "not published", wantErr: true},
+		{output: "no port\n", message: "invalid", wantErr: true},
+	}
+	for _, tt := range tests {
+		binding, err := parsePortOutput([]byte(tt.output))
+		if tt.wantErr {
+			assert.Error(t, err, tt.message)
+			continue
+		}
+		assert.NoError(t, err, tt.message)
+		assert.Equal(t, tt.expected, binding, tt.message)
+	}
+	assert.Equal(t, "[::]:9080", PortBinding{HostIP: "::", HostPort: 9080}.String())
+}
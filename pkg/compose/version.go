@@ -0,0 +1,217 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Version is the semantic version of the docker compose cli, e.g. 2.27.0
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+	// Prerelease is the part after the patch version without the leading dash, e.g. "desktop.1" or "rc.2"
+	Prerelease string
+}
+
+var versionPattern = regexp.MustCompile(`v?(\d+)\.(\d+)(?:\.(\d+))?(?:-([0-9A-Za-z.\-]+))?`)
+
+// ParseVersion parses a compose version such as "2.27.0", "v2.27.0-desktop.1"
+// or the full output of docker compose version, e.g. "Docker Compose version v2.27.0".
+func ParseVersion(version string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(version)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid compose version %q", strings.TrimSpace(version))
+	}
+	v := Version{Prerelease: m[4]}
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+// String returns the version without the leading v, e.g. "2.27.0"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+	return s
+}
+
+// Compare returns -1, 0 or 1 when v is lower than, equal to or greater than other.
+// Prereleases only compare lower than their release when they are not a distribution suffix
+// such as "desktop.1", which docker desktop appends to released versions.
+func (v Version) Compare(other Version) int {
+	for _, d := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if d[0] != d[1] {
+			if d[0] < d[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch a, b := v.isPrerelease(), other.isPrerelease(); {
+	case a && b:
+		return strings.Compare(v.Prerelease, other.Prerelease)
+	case a:
+		return -1
+	case b:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether v is the same as or newer than other
+func (v Version) AtLeast(other Version) bool {
+	return v.Compare(other) >= 0
+}
+
+func (v Version) isPrerelease() bool {
+	return v.Prerelease != "" && !strings.HasPrefix(v.Prerelease, "desktop")
+}
+
+// Version runs docker compose version and returns the parsed version of the cli.
+// The version is detected once per compose instance, a failed detection is not retried
+// unless it was stopped by the context. Use WithVersion to skip the detection.
+func (c *compose) Version(ctx context.Context) (Version, error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+	if c.version != nil {
+		return *c.version, nil
+	}
+	if c.versionErr != nil {
+		return Version{}, c.versionErr
+	}
+	cmd, err := c.globalCommand(nil, []string{"version", "--short"})
+	if err != nil {
+		return Version{}, err
+	}
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	// the version is a query and is detected even in dry run mode
+	if err := c.exec(ctx, cmd); err != nil {
+		err = NewComposeError(fmt.Errorf("failed to detect compose version: %w", err))
+		if ctx.Err() == nil {
+			c.versionErr = err
+		}
+		return Version{}, err
+	}
+	v, err := ParseVersion(stdout.String())
+	if err != nil {
+		c.versionErr = NewComposeError(err)
+		return Version{}, c.versionErr
+	}
+	c.version = &v
+	return v, nil
+}
+
+// WithVersion sets the version of the compose cli instead of detecting it with docker compose version,
+// e.g. WithVersion("2.27.0"). Flags are validated against this version.
+func WithVersion(version string) SetComposeOption {
+	return func(c *compose) error {
+		v, err := ParseVersion(version)
+		if err != nil {
+			return NewComposeError(fmt.Errorf("WithVersion: %w", err))
+		}
+		c.version = &v
+		return nil
+	}
+}
+
+// flagRequirement is the minimum compose version of a command or one of its flags
+type flagRequirement struct {
+	command string
+	// flag is empty when the command itself requires the version
+	flag  string
+	since Version
+	// option is the setter that produces the flag, used in the error message
+	option string
+}
+
+// flagRequirements are the commands and flags that are not supported by every compose v2 release.
+// Flags of a command which were available when the command was added are not listed.
+var flagRequirements = []flagRequirement{
+	{command: "up", flag: "--wait", since: Version{Major: 2, Minor: 1, Patch: 1}, option: "up.WithWait"},
+	{command: "up", flag: "--wait-timeout", since: Version{Major: 2, Minor: 14}, option: "up.WithWaitTimeout"},
+	{command: "up", flag: "--watch", since: Version{Major: 2, Minor: 22}, option: "up.WithWatch"},
+	{command: "up", flag: "--menu", since: Version{Major: 2, Minor: 26}, option: "up.WithMenu"},
+	{command: "up", flag: "--abort-on-container-failure", since: Version{Major: 2, Minor: 26}, option: "up.WithAbortOnContainerFailure"},
+	{command: "watch", since: Version{Major: 2, Minor: 22}, option: "Watch"},
+	{command: "watch", flag: "--no-up", since: Version{Major: 2, Minor: 23}, option: "watch.WithNoUp"},
+	{command: "watch", flag: "--prune", since: Version{Major: 2, Minor: 29}, option: "watch.WithPrune"},
+	{command: "wait", since: Version{Major: 2, Minor: 20}, option: "Wait"},
+	{command: "attach", since: Version{Major: 2, Minor: 20}, option: "Attach"},
+	{command: "stats", since: Version{Major: 2, Minor: 20}, option: "Stats"},
+	{command: "scale", since: Version{Major: 2, Minor: 23}, option: "Scale"},
+	{command: "publish", since: Version{Major: 2, Minor: 34}, option: "Publish"},
+}
+
+// checkFlagSupport returns a ComposeFlagError for every command or flag the version does not support
+func checkFlagSupport(version Version, flags []string) error {
+	if len(flags) == 0 {
+		return nil
+	}
+	for _, req := range flagRequirements {
+		if req.command != flags[0] || version.AtLeast(req.since) {
+			continue
+		}
+		if req.flag == "" {
+			return NewComposeFlagError(req.command, fmt.Sprintf("%s requires docker compose %s or later, found %s", req.option, req.since, version))
+		}
+		if slices.Contains(flags[1:], req.flag) {
+			return NewComposeFlagError(req.flag, fmt.Sprintf("%s requires docker compose %s or later, found %s", req.option, req.since, version))
+		}
+	}
+	return nil
+}
+
+// requiresVersion reports whether any of the flags depends on the compose version
+func requiresVersion(flags []string) bool {
+	for _, req := range flagRequirements {
+		if len(flags) > 0 && req.command == flags[0] && (req.flag == "" || slices.Contains(flags[1:], req.flag)) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagGenerator is implemented by the options of every command
+type flagGenerator interface {
+	GenerateFlags() ([]string, error)
+}
+
+// generateFlags generates the flags of the options and validates them against the compose version.
+// The version is only detected when a flag depends on it, when the detection fails the flags are passed as is
+// and compose reports unsupported flags itself. A failed detection is cached, see Version.
+func (c *compose) generateFlags(ctx context.Context, opt flagGenerator) ([]string, error) {
+	flags, err := opt.GenerateFlags()
+	if err != nil {
+		return nil, err
+	}
+	if !requiresVersion(flags) {
+		return flags, nil
+	}
+	c.versionMu.Lock()
+	known := c.version != nil
+	c.versionMu.Unlock()
+	if !known && c.skipsExecution() {
+		// nothing is executed in dry run mode, unless the version was set with WithVersion
+		return flags, nil
+	}
+	version, err := c.Version(ctx)
+	if err != nil {
+		return flags, nil
+	}
+	if err := checkFlagSupport(version, flags); err != nil {
+		return nil, err
+	}
+	return flags, nil
+}
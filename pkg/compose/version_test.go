@@ -0,0 +1,194 @@
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{"2.27.0", Version{Major: 2, Minor: 27}},
+		{"v2.27.1\n", Version{Major: 2, Minor: 27, Patch: 1}},
+		{"2.29.1-desktop.1", Version{Major: 2, Minor: 29, Patch: 1, Prerelease: "desktop.1"}},
+		{"Docker Compose version v2.30.0-rc.2", Version{Major: 2, Minor: 30, Prerelease: "rc.2"}},
+		{"1.29.2", Version{Major: 1, Minor: 29, Patch: 2}},
+		{"2.3", Version{Major: 2, Minor: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVersion(tt.input)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+	_, err := ParseVersion("dev")
+	assert.EqualError(t, err, `invalid compose version "dev"`)
+}
+
+func TestVersionCompare(t *testing.T) {
+	v := func(s string) Version {
+		version, err := ParseVersion(s)
+		assert.NoError(t, err)
+		return version
+	}
+	assert.Equal(t, 0, v("2.27.0").Compare(v("v2.27.0")))
+	assert.Equal(t, -1, v("2.9.0").Compare(v("2.27.0")))
+	assert.Equal(t, 1, v("3.0.0").Compare(v("2.99.99")))
+	assert.Equal(t, -1, v("2.30.0-rc.1").Compare(v("2.30.0")))
+	assert.Equal(t, -1, v("2.30.0-rc.1").Compare(v("2.30.0-rc.2")))
+	// docker desktop builds are releases
+	assert.Equal(t, 0, v("2.29.1-desktop.1").Compare(v("2.29.1")))
+	assert.True(t, v("2.29.1-desktop.1").AtLeast(v("2.26.0")))
+	assert.Equal(t, "2.30.0-rc.1", v("v2.30.0-rc.1").String())
+}
+
+func TestVersionDetection(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		if cmd.Args[1] == "version" {
+			_, err := fmt.Fprintln(cmd.Stdout, "2.20.3")
+			return err
+		}
+		return nil
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+	ctx := context.Background()
+
+	version, err := c.Version(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 2, Minor: 20, Patch: 3}, version)
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "version", "--short"}, cmd.Argv)
+
+	// the version is cached
+	_, err = c.Version(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, runner.Commands(), 1)
+
+	// supported flags pass
+	err = c.Up(ctx, func(opt *ComposeUpOptions) error {
+		opt.Wait = true
+		return nil
+	})
+	assert.NoError(t, err)
+	cmd, _ = runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "up", "--wait"}, cmd.Argv)
+
+	// unsupported flags are rejected before compose is executed
+	runner.Reset()
+	err = c.Up(ctx, func(opt *ComposeUpOptions) error {
+		opt.Menu = true
+		return nil
+	})
+	assert.True(t, IsComposeUpError(err))
+	var flagErr *ComposeFlagError
+	if assert.True(t, errors.As(err, &flagErr)) {
+		assert.Equal(t, "--menu", flagErr.Flag)
+		assert.Equal(t, "up.WithMenu requires docker compose 2.26.0 or later, found 2.20.3", flagErr.Message)
+	}
+	assert.Empty(t, runner.Commands())
+}
+
+func TestWithVersion(t *testing.T) {
+	runner := NewRecordingRunner()
+	c := NewCompose(testProject(), WithRunner(runner), WithVersion("2.19.0"))
+	ctx := context.Background()
+
+	version, err := c.Version(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Version{Major: 2, Minor: 19}, version)
+
+	err = c.Wait(ctx, func(opt *ComposeWaitOptions) error {
+		opt.ServiceNames = []string{"web"}
+		return nil
+	})
+	assert.True(t, IsComposeWaitError(err))
+	assert.True(t, IsComposeFlagError(err))
+	assert.Empty(t, runner.Commands())
+
+	c = NewCompose(testProject(), WithVersion("latest"))
+	assert.True(t, IsComposeDownError(c.Down(ctx)))
+}
+
+func TestVersionDetectionFailure(t *testing.T) {
+	runner := NewRecordingRunner()
+	runner.Respond = func(ctx context.Context, cmd *Command) error {
+		if cmd.Args[1] == "version" {
+			return &testExitError{code: 1}
+		}
+		return nil
+	}
+	c := NewCompose(testProject(), WithRunner(runner))
+	ctx := context.Background()
+
+	_, err := c.Version(ctx)
+	assert.True(t, IsComposeError(err))
+
+	// flags are passed to compose when the version is unknown
+	err = c.Up(ctx, func(opt *ComposeUpOptions) error {
+		opt.Menu = true
+		return nil
+	})
+	assert.NoError(t, err)
+	cmd, _ := runner.Last()
+	assert.Equal(t, []string{"docker", "compose", "-f", "-", "up", "--menu"}, cmd.Argv)
+
+	// the failed detection is cached and not run again before every command
+	runner.Reset()
+	assert.NoError(t, c.Up(ctx, func(opt *ComposeUpOptions) error {
+		opt.Menu = true
+		return nil
+	}))
+	_, err = c.Version(ctx)
+	assert.True(t, IsComposeError(err))
+	assert.Len(t, runner.Commands(), 1)
+
+	// a detection stopped by the context is retried
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	c = NewCompose(testProject(), WithRunner(runner))
+	runner.Reset()
+	_, err = c.Version(canceled)
+	assert.True(t, IsComposeError(err))
+	_, err = c.Version(ctx)
+	assert.True(t, IsComposeError(err))
+	assert.Len(t, runner.Commands(), 2)
+}
+
+func TestCheckFlagSupport(t *testing.T) {
+	v := Version{Major: 2, Minor: 20}
+	tests := []struct {
+		flags []string
+		flag  string
+	}{
+		{flags: []string{"attach", "web"}},
+		{flags: []string{"stats", "--format", "json"}},
+		{flags: []string{"wait", "web"}},
+		{flags: []string{"scale", "web=3"}, flag: "scale"},
+		{flags: []string{"publish", "registry.example.com/stack:1.0"}, flag: "publish"},
+		{flags: []string{"watch"}, flag: "watch"},
+		{flags: []string{"up", "--menu"}, flag: "--menu"},
+	}
+	for _, tt := range tests {
+		err := checkFlagSupport(v, tt.flags)
+		if tt.flag == "" {
+			assert.NoError(t, err, tt.flags)
+			continue
+		}
+		var flagErr *ComposeFlagError
+		if assert.ErrorAs(t, err, &flagErr, tt.flags) {
+			assert.Equal(t, tt.flag, flagErr.Flag)
+		}
+	}
+	assert.Error(t, checkFlagSupport(Version{Major: 2, Minor: 19}, []string{"attach", "web"}))
+	assert.Error(t, checkFlagSupport(Version{Major: 2, Minor: 19}, []string{"stats"}))
+	assert.NoError(t, checkFlagSupport(Version{Major: 2, Minor: 23}, []string{"watch", "--no-up"}))
+	assert.Error(t, checkFlagSupport(Version{Major: 2, Minor: 23}, []string{"watch", "--prune"}))
+}
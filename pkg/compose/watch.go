@@ -0,0 +1,181 @@
+package compose
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// WatchAction is the kind of notification printed by docker compose watch
+type WatchAction string
+
+const (
+	// WatchActionEnabled is emitted once compose starts watching the build contexts
+	WatchActionEnabled WatchAction = "enabled"
+	// WatchActionSync is emitted when changed files are synced into a service container
+	WatchActionSync WatchAction = "sync"
+	// WatchActionRebuild is emitted when services are rebuilt after changes were detected
+	WatchActionRebuild WatchAction = "rebuild"
+	// WatchActionBuilt is emitted when a rebuild of services finished successfully
+	WatchActionBuilt WatchAction = "built"
+	// WatchActionRestart is emitted when a service container is restarted after a sync
+	WatchActionRestart WatchAction = "restart"
+	// WatchActionOutput is any other output line of the command (e.g. build progress)
+	WatchActionOutput WatchAction = "output"
+)
+
+// WatchEvent is a parsed notification of docker compose watch
+type WatchEvent struct {
+	Action WatchAction
+	// Services are the services the notification is about, empty for enabled and output
+	Services []string
+	// Changes is the number of changed files of a sync, 0 when compose does not report it
+	Changes int
+	// Message is the original output line without ansi escapes and prefix
+	Message string
+}
+
+var (
+	ansiEscape     = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	quotedName     = regexp.MustCompile(`"([^"]+)"`)
+	watchEnabledRe = regexp.MustCompile(`^Watch enabled`)
+	watchSyncRe    = regexp.MustCompile(`^Syncing (?:service )?(.+?) after (?:(\d+) )?changes? (?:were|was) detected`)
+	watchRebuildRe = regexp.MustCompile(`^Rebuilding service(?:\(s\))? (.+?) after changes? (?:were|was) detected`)
+	watchBuiltRe   = regexp.MustCompile(`^service(?:\(s\))? (.+?) successfully built`)
+	watchRestartRe = regexp.MustCompile(`^(?:service(?:\(s\))? (.+?) restarted|Restarting (?:service(?:\(s\))? )?(.+?) after changes? (?:were|was) detected)`)
+)
+
+// Watch runs the docker compose watch command and streams parsed notifications on the returned channel.
+// Services must have a develop section (see sc.WithDevelop). Both channels are closed when the command
+// exits, cancel the context to stop watching.
+// The writer set with watch.WithWriter receives the raw output as well.
+func (c *compose) Watch(ctx context.Context, setters ...SetComposeWatchOption) (<-chan WatchEvent, <-chan error, error) {
+	opt := &ComposeWatchOptions{Writer: os.Stdout}
+	for _, setter := range setters {
+		if err := setter(opt); err != nil {
+			return nil, nil, NewComposeWatchError(err)
+		}
+	}
+	flags, err := c.generateFlags(ctx, opt)
+	if err != nil {
+		return nil, nil, NewComposeWatchError(err)
+	}
+	flags = append(flags, opt.ServiceNames...)
+	watchCh := make(chan WatchEvent, 1)
+	errCh := make(chan error, 1)
+
+	writer := io.MultiWriter(opt.Writer, newWatchWriter(ctx, watchCh))
+	cmd, err := c.command(ctx, writer, flags, opt.Profiles, nil)
+	if err != nil {
+		return nil, nil, NewComposeWatchError(err)
+	}
+
+	go func() {
+		defer close(watchCh)
+		defer close(errCh)
+
+		if err := handleContextCancellation(ctx, c.run(ctx, cmd)); err != nil {
+			errCh <- NewComposeWatchError(err)
+		}
+	}()
+
+	return watchCh, errCh, nil
+}
+
+type watchWriter struct {
+	ctx    context.Context
+	ch     chan WatchEvent
+	buffer bytes.Buffer
+}
+
+func (w *watchWriter) Write(p []byte) (n int, err error) {
+	n, err = w.buffer.Write(p)
+	if err != nil {
+		return n, err
+	}
+
+	for {
+		line, err := w.buffer.ReadBytes('\n')
+		if err == io.EOF {
+			// Incomplete line; keep in buffer
+			w.buffer.Write(line)
+			break
+		} else if err != nil {
+			return n, err
+		}
+		event, ok := parseWatchLine(string(line))
+		if !ok {
+			continue
+		}
+		select {
+		case <-w.ctx.Done():
+			w.buffer.Reset()
+			return n, w.ctx.Err()
+		case w.ch <- event:
+		}
+	}
+
+	return n, nil
+}
+
+func newWatchWriter(ctx context.Context, ch chan WatchEvent) io.Writer {
+	return &watchWriter{ctx: ctx, ch: ch}
+}
+
+// parseWatchLine parses a single output line of docker compose watch,
+// it returns false for blank lines
+func parseWatchLine(line string) (WatchEvent, bool) {
+	line = ansiEscape.ReplaceAllString(line, "")
+	line = strings.TrimSpace(line)
+	// depending on the compose version notifications are prefixed with a symbol or a "watch |" log prefix
+	if prefix, rest, ok := strings.Cut(line, "| "); ok && strings.TrimSpace(prefix) == "watch" {
+		line = rest
+	}
+	line = strings.TrimLeftFunc(line, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z')
+	})
+	if line == "" {
+		return WatchEvent{}, false
+	}
+	event := WatchEvent{Action: WatchActionOutput, Message: line}
+	switch {
+	case watchEnabledRe.MatchString(line):
+		event.Action = WatchActionEnabled
+	case watchSyncRe.MatchString(line):
+		m := watchSyncRe.FindStringSubmatch(line)
+		event.Action = WatchActionSync
+		event.Services = parseServiceNames(m[1])
+		event.Changes, _ = strconv.Atoi(m[2])
+	case watchRebuildRe.MatchString(line):
+		event.Action = WatchActionRebuild
+		event.Services = parseServiceNames(watchRebuildRe.FindStringSubmatch(line)[1])
+	case watchBuiltRe.MatchString(line):
+		event.Action = WatchActionBuilt
+		event.Services = parseServiceNames(watchBuiltRe.FindStringSubmatch(line)[1])
+	case watchRestartRe.MatchString(line):
+		m := watchRestartRe.FindStringSubmatch(line)
+		event.Action = WatchActionRestart
+		event.Services = parseServiceNames(m[1] + m[2])
+	}
+	return event, true
+}
+
+// parseServiceNames extracts the service names from a formatted value like `"web"`, `["web" "api"]` or `web, api`
+func parseServiceNames(value string) []string {
+	if matches := quotedName.FindAllStringSubmatch(value, -1); len(matches) > 0 {
+		names := make([]string, 0, len(matches))
+		for _, m := range matches {
+			names = append(names, m[1])
+		}
+		return names
+	}
+	names := []string{}
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '[' || r == ']' }) {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,44 @@
+package compose
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchWriter(t *testing.T) {
+	data, err := os.ReadFile("testdata/watch.txt")
+	if !assert.NoError(t, err) {
+		return
+	}
+	ch := make(chan WatchEvent, 20)
+	w := newWatchWriter(context.Background(), ch)
+	for i := 0; i < len(data); i += 5 {
+		end := min(i+5, len(data))
+		_, err := w.Write(data[i:end])
+		assert.NoError(t, err)
+	}
+	close(ch)
+	events := []WatchEvent{}
+	for e := range ch {
+		events = append(events, e)
+	}
+	assert.Equal(t, []WatchEvent{
+		{Action: WatchActionOutput, Message: "Running 2/2"},
+		{Action: WatchActionOutput, Message: "Container demo-web-1  Started"},
+		{Action: WatchActionEnabled, Message: "Watch enabled"},
+		{Action: WatchActionSync, Services: []string{"web"}, Changes: 3, Message: `Syncing service "web" after 3 changes were detected`},
+		{Action: WatchActionSync, Services: []string{"api"}, Message: `Syncing "api" after changes were detected`},
+		{Action: WatchActionRebuild, Services: []string{"web", "api"}, Message: `Rebuilding service(s) ["web" "api"] after changes were detected...`},
+		{Action: WatchActionBuilt, Services: []string{"web", "api"}, Message: `service(s) ["web" "api"] successfully built`},
+		{Action: WatchActionRestart, Services: []string{"web"}, Message: `service(s) ["web"] restarted`},
+	}, events)
+}
+
+func TestParseServiceNames(t *testing.T) {
+	assert.Equal(t, []string{"web"}, parseServiceNames(`"web"`))
+	assert.Equal(t, []string{"web", "api"}, parseServiceNames(`["web" "api"]`))
+	assert.Equal(t, []string{"web", "api"}, parseServiceNames(`web, api`))
+}
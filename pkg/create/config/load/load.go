@@ -0,0 +1,79 @@
+// Package load provides options for loading a project from compose files with create.LoadProject
+package load
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+)
+
+// WithName sets the name of the project, overriding the name of the compose files
+// parameters:
+//   - name: the name of the project
+func WithName(name string) create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.Name = name
+		return nil
+	}
+}
+
+// WithWorkingDirectory sets the directory relative paths are resolved against
+// parameters:
+//   - dir: the working directory, defaults to the directory of the first compose file
+func WithWorkingDirectory(dir string) create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.WorkingDir = dir
+		return nil
+	}
+}
+
+// WithEnvFiles sets the env files used for interpolation instead of the .env file of the working directory
+// parameters:
+//   - files: the paths of the env files, later files take precedence
+func WithEnvFiles(files ...string) create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.EnvFiles = append(opt.EnvFiles, files...)
+		return nil
+	}
+}
+
+// WithEnv sets a variable used for interpolation, it takes precedence over the os environment and env files
+// parameters:
+//   - key: the name of the variable
+//   - value: the value of the variable
+func WithEnv(key, value string) create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		if key == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("invalid env key %q", key)
+		}
+		opt.Env = append(opt.Env, fmt.Sprintf("%s=%s", key, value))
+		return nil
+	}
+}
+
+// WithProfiles activates profiles, services of profiles which are not active are disabled
+// parameters:
+//   - profiles: the profiles to activate, "*" activates all profiles
+func WithProfiles(profiles ...string) create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.Profiles = append(opt.Profiles, profiles...)
+		return nil
+	}
+}
+
+// WithoutOsEnv excludes the environment of the current process from interpolation
+func WithoutOsEnv() create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.NoOsEnv = true
+		return nil
+	}
+}
+
+// WithNoInterpolate disables the interpolation of variables, e.g. ${TAG} is kept as is
+func WithNoInterpolate() create.SetLoadOption {
+	return func(opt *create.LoadOptions) error {
+		opt.NoInterpolate = true
+		return nil
+	}
+}
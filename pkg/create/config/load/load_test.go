@@ -0,0 +1,162 @@
+package load_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/aptd3v/go-contain/pkg/create/config/load"
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadProject(t *testing.T) {
+	ctx := context.Background()
+	files := []string{"testdata/compose.yaml", "testdata/compose.override.yaml"}
+	dir, err := filepath.Abs("testdata")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	project, err := create.LoadProject(ctx, files, load.WithoutOsEnv())
+	if !assert.NoError(t, err) {
+		return
+	}
+	p := project.Unwrap()
+	assert.Equal(t, "shop", p.Name)
+	assert.Equal(t, dir, p.WorkingDir)
+	assert.Equal(t, []string{"web"}, p.ServiceNames())
+	web, err := project.GetService("web")
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the .env file of the working directory is used for interpolation
+	assert.Equal(t, "nginx:1.27", web.Image)
+	assert.Equal(t, "info", *web.Environment["LEVEL"])
+	// relative paths are resolved against the working directory
+	assert.Equal(t, filepath.Join(dir, "html"), web.Volumes[0].Source)
+
+	// the loaded project can be extended and marshaled
+	project.WithService("worker", create.NewContainer("worker").WithContainerConfig(cc.WithImage("busybox")))
+	assert.NoError(t, project.Validate())
+	data, err := project.Marshal()
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "worker:")
+}
+
+func TestLoadProjectOptions(t *testing.T) {
+	ctx := context.Background()
+	files := []string{"testdata/compose.yaml"}
+
+	project, err := create.LoadProject(ctx, files,
+		load.WithoutOsEnv(),
+		load.WithName("staging"),
+		load.WithEnvFiles("testdata/prod.env"),
+		load.WithProfiles("debug"),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	p := project.Unwrap()
+	assert.Equal(t, "staging", p.Name)
+	assert.Equal(t, []string{"debug", "web"}, p.ServiceNames())
+	assert.Equal(t, "nginx:1.25", p.Services["web"].Image)
+
+	// variables set with WithEnv take precedence over env files
+	project, err = create.LoadProject(ctx, files, load.WithoutOsEnv(), load.WithEnv("TAG", "1.29"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "nginx:1.29", project.Unwrap().Services["web"].Image)
+	}
+
+	project, err = create.LoadProject(ctx, files, load.WithoutOsEnv(), load.WithNoInterpolate())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "nginx:${TAG:-latest}", project.Unwrap().Services["web"].Image)
+	}
+
+	// the compose file is found in the working directory
+	project, err = create.LoadProject(ctx, nil, load.WithoutOsEnv(), load.WithWorkingDirectory("testdata"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, "shop", project.Unwrap().Name)
+	}
+}
+
+func TestLoadProjectErrors(t *testing.T) {
+	ctx := context.Background()
+	_, err := create.LoadProject(ctx, []string{"testdata/missing.yaml"})
+	assert.True(t, errdefs.IsProjectConfigError(err))
+
+	_, err = create.LoadProject(ctx, []string{"testdata/compose.yaml"}, load.WithEnv("", "value"))
+	assert.True(t, errdefs.IsProjectConfigError(err))
+
+	_, err = create.LoadProject(ctx, []string{"testdata/compose.yaml"}, load.WithName("Invalid Name"))
+	assert.True(t, errdefs.IsProjectConfigError(err))
+}
+
+func TestLoadProjectEscapes(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "compose.yaml")
+	content := `
+name: app
+services:
+  web:
+    image: busybox
+    command: echo $$HOME
+    environment:
+      PASSWORD: ${PASSWORD}
+`
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	project, err := create.LoadProject(ctx, []string{file}, load.WithoutOsEnv(), load.WithEnv("PASSWORD", "pa$word"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the loaded values are interpolated and unescaped
+	web, _ := project.GetService("web")
+	assert.Equal(t, types.ShellCommand{"echo", "$HOME"}, web.Command)
+	assert.Equal(t, "pa$word", *web.Environment["PASSWORD"])
+	err = project.ForEachService(func(name string, service *types.ServiceConfig) error {
+		assert.Equal(t, "pa$word", *service.Environment["PASSWORD"])
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// the marshaled values are escaped, docker compose interpolates the marshaled project again
+	data, err := project.Marshal()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, string(data), "pa$$word")
+	assert.Contains(t, string(data), "$$HOME")
+	marshaled := filepath.Join(dir, "marshaled.yaml")
+	if err := os.WriteFile(marshaled, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := create.LoadProject(ctx, []string{marshaled}, load.WithoutOsEnv(), load.WithEnv("HOME", "/root"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	web, _ = reloaded.GetService("web")
+	assert.Equal(t, types.ShellCommand{"echo", "$HOME"}, web.Command)
+	assert.Equal(t, "pa$word", *web.Environment["PASSWORD"])
+
+	// a project merged with a loaded project is escaped as well
+	merged := create.NewProject("app").Merge(project)
+	data, err = merged.Marshal()
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), "pa$$word")
+	}
+
+	// without interpolation the values are kept as written
+	project, err = create.LoadProject(ctx, []string{file}, load.WithoutOsEnv(), load.WithNoInterpolate())
+	if assert.NoError(t, err) {
+		web, _ = project.GetService("web")
+		assert.Equal(t, types.ShellCommand{"echo", "$$HOME"}, web.Command)
+		assert.Equal(t, "${PASSWORD}", *web.Environment["PASSWORD"])
+	}
+}
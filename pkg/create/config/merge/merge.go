@@ -0,0 +1,30 @@
+// Package merge provides options for merging projects with create.Project.Merge
+//
+// Paths are the dotted keys of the compose model, e.g. "services.web.ports",
+// * matches any key, e.g. "services.*.ports"
+package merge
+
+import (
+	"github.com/aptd3v/go-contain/pkg/create"
+)
+
+// WithReset removes the value at path from the merged project, the equivalent of the !reset yaml tag
+// parameters:
+//   - path: the path of the value to remove, e.g. "services.web.ports"
+func WithReset(path string) create.MergeOption {
+	return func(opt *create.MergeOptions) error {
+		opt.Reset = append(opt.Reset, path)
+		return nil
+	}
+}
+
+// WithOverride replaces the value at path with the value of the merged project instead of merging both values,
+// the equivalent of the !override yaml tag. Values which are not set by the merged project are kept.
+// parameters:
+//   - path: the path of the value to replace, e.g. "services.web.environment"
+func WithOverride(path string) create.MergeOption {
+	return func(opt *create.MergeOptions) error {
+		opt.Override = append(opt.Override, path)
+		return nil
+	}
+}
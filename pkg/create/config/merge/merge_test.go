@@ -0,0 +1,286 @@
+package merge_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/aptd3v/go-contain/pkg/create/config/hc"
+	"github.com/aptd3v/go-contain/pkg/create/config/load"
+	"github.com/aptd3v/go-contain/pkg/create/config/merge"
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// loadYaml loads a project from yaml, the examples mirror the merge section of the compose specification
+func loadYaml(t *testing.T, name, content string) *create.Project {
+	t.Helper()
+	dir := t.TempDir()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	project, err := create.LoadProject(context.Background(), []string{file}, load.WithoutOsEnv())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return project
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		message  string
+		base     string
+		override string
+		setters  []create.MergeOption
+		check    func(t *testing.T, project *types.Project)
+	}{
+		{
+			message: "single values are replaced",
+			base: `
+name: app
+services:
+  web:
+    image: myapp:1
+    command: ["run", "--debug"]
+`,
+			override: `
+name: app-prod
+services:
+  web:
+    image: myapp:2
+    command: ["run"]
+`,
+			check: func(t *testing.T, project *types.Project) {
+				assert.Equal(t, "app", project.Name)
+				assert.Equal(t, "myapp:2", project.Services["web"].Image)
+				assert.Equal(t, types.ShellCommand{"run"}, project.Services["web"].Command)
+			},
+		},
+		{
+			message: "mappings are merged",
+			base: `
+name: app
+services:
+  web:
+    image: myapp
+    environment:
+      A: "1"
+      B: "2"
+    labels:
+      tier: web
+`,
+			override: `
+name: app
+services:
+  web:
+    image: myapp
+    environment:
+      B: "3"
+      C: "4"
+    labels:
+      env: prod
+`,
+			check: func(t *testing.T, project *types.Project) {
+				web := project.Services["web"]
+				assert.Equal(t, types.NewMappingWithEquals([]string{"A=1", "B=3", "C=4"}), web.Environment)
+				assert.Equal(t, types.Labels{"tier": "web", "env": "prod"}, web.Labels)
+			},
+		},
+		{
+			message: "sequences are appended and unique",
+			base: `
+name: app
+services:
+  web:
+    image: myapp
+    ports:
+      - "8080:80"
+    volumes:
+      - ./data:/data
+    dns:
+      - 1.1.1.1
+`,
+			override: `
+name: app
+services:
+  web:
+    image: myapp
+    ports:
+      - "8080:80"
+      - "8443:443"
+    volumes:
+      - ./prod:/data
+      - ./logs:/logs
+    dns:
+      - 8.8.8.8
+`,
+			check: func(t *testing.T, project *types.Project) {
+				web := project.Services["web"]
+				assert.Len(t, web.Ports, 2)
+				assert.Equal(t, "8443", web.Ports[1].Published)
+				if assert.Len(t, web.Volumes, 2) {
+					assert.Equal(t, "/data", web.Volumes[0].Target)
+					assert.Equal(t, "prod", filepath.Base(web.Volumes[0].Source))
+				}
+				assert.Equal(t, types.StringList{"1.1.1.1", "8.8.8.8"}, web.DNS)
+			},
+		},
+		{
+			message: "services and top level elements are added",
+			base: `
+name: app
+services:
+  web:
+    image: myapp
+networks:
+  front: {}
+volumes:
+  db:
+    driver: local
+secrets:
+  token:
+    file: ./token.txt
+`,
+			override: `
+name: app
+services:
+  worker:
+    image: worker
+networks:
+  back:
+    internal: true
+volumes:
+  db:
+    labels:
+      backup: daily
+secrets:
+  key:
+    environment: KEY
+`,
+			check: func(t *testing.T, project *types.Project) {
+				assert.Equal(t, []string{"web", "worker"}, project.ServiceNames())
+				assert.Contains(t, project.Networks, "front")
+				assert.True(t, project.Networks["back"].Internal)
+				assert.Equal(t, "local", project.Volumes["db"].Driver)
+				assert.Equal(t, types.Labels{"backup": "daily"}, project.Volumes["db"].Labels)
+				assert.Contains(t, project.Secrets, "token")
+				assert.Equal(t, "KEY", project.Secrets["key"].Environment)
+			},
+		},
+		{
+			message: "WithReset removes the value",
+			base: `
+name: app
+services:
+  web:
+    image: myapp
+    ports:
+      - "8080:80"
+`,
+			override: `
+name: app
+services:
+  web:
+    image: myapp
+    ports:
+      - "9090:80"
+`,
+			setters: []create.MergeOption{merge.WithReset("services.web.ports")},
+			check: func(t *testing.T, project *types.Project) {
+				assert.Empty(t, project.Services["web"].Ports)
+				assert.Equal(t, "myapp", project.Services["web"].Image)
+			},
+		},
+		{
+			message: "WithOverride replaces the value",
+			base: `
+name: app
+services:
+  web:
+    image: myapp
+    environment:
+      A: "1"
+  worker:
+    image: worker
+    environment:
+      A: "1"
+`,
+			override: `
+name: app
+services:
+  web:
+    image: myapp
+    environment:
+      B: "2"
+`,
+			setters: []create.MergeOption{merge.WithOverride("services.*.environment")},
+			check: func(t *testing.T, project *types.Project) {
+				assert.Equal(t, types.NewMappingWithEquals([]string{"B=2"}), project.Services["web"].Environment)
+				// worker is not redefined and keeps its environment
+				assert.Equal(t, types.NewMappingWithEquals([]string{"A=1"}), project.Services["worker"].Environment)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			base := loadYaml(t, "compose.yaml", tt.base)
+			override := loadYaml(t, "compose.override.yaml", tt.override)
+			project := base.Merge(override, tt.setters...)
+			if !assert.NoError(t, project.Validate()) {
+				return
+			}
+			tt.check(t, project.Unwrap())
+		})
+	}
+}
+
+func TestMergeProjects(t *testing.T) {
+	base := create.NewProject("app").
+		WithService("web", create.NewContainer("web").
+			WithContainerConfig(cc.WithImage("myapp:1"), cc.WithEnv("LEVEL", "debug"), cc.WithEnv("PORT", "80")).
+			WithHostConfig(hc.WithPortBindings("tcp", "0.0.0.0", "8080", "80")),
+		)
+	prod := create.NewProject("prod").
+		WithService("web", create.NewContainer("web").
+			WithContainerConfig(cc.WithImage("myapp:2"), cc.WithEnv("LEVEL", "info")).
+			WithHostConfig(hc.WithPortBindings("tcp", "0.0.0.0", "8080", "80")),
+		).
+		WithVolume("data")
+
+	project := base.Merge(prod)
+	if !assert.NoError(t, project.Validate()) {
+		return
+	}
+	web, err := project.GetService("web")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "app", project.Unwrap().Name)
+	assert.Equal(t, "myapp:2", web.Image)
+	assert.Equal(t, "info", *web.Environment["LEVEL"])
+	assert.Equal(t, "80", *web.Environment["PORT"])
+	assert.Len(t, web.Ports, 1)
+	assert.Equal(t, "web", web.ContainerName)
+	assert.Contains(t, project.Unwrap().Volumes, "data")
+}
+
+func TestMergeErrors(t *testing.T) {
+	base := create.NewProject("app").WithService("web", create.NewContainer("web"), func(service *types.ServiceConfig) error {
+		service.Image = "myapp"
+		return nil
+	})
+	assert.True(t, errdefs.IsProjectConfigError(base.Merge(nil).Validate()))
+
+	base = create.NewProject("app").WithService("web", create.NewContainer("web"), func(service *types.ServiceConfig) error {
+		service.Image = "myapp"
+		return nil
+	})
+	err := base.Merge(create.NewProject("prod"), merge.WithReset("services..ports")).Validate()
+	assert.True(t, errdefs.IsProjectConfigError(err))
+	assert.ErrorContains(t, err, `invalid merge path "services..ports"`)
+}
@@ -0,0 +1,83 @@
+// Package configservice provides a set of functions to configure the configs for the service
+package configservice
+
+import (
+	"fmt"
+
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// SetConfigServiceConfig is a function that sets the config for the service
+type SetConfigServiceConfig func(*types.ServiceConfigObjConfig) error
+
+// WithSource sets the source for the config
+// parameters:
+//   - source: the name of the config in the configs section of the project
+func WithSource(source string) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		opt.Source = source
+		return nil
+	}
+}
+
+// WithTarget sets the target for the config
+// parameters:
+//   - target: the path the config is mounted at in the container, defaults to /<source>
+func WithTarget(target string) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		opt.Target = target
+		return nil
+	}
+}
+
+// WithUID sets the UID for the config
+// parameters:
+//   - uid: the UID for the config
+func WithUID(uid string) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		opt.UID = uid
+		return nil
+	}
+}
+
+// WithGID sets the GID for the config
+// parameters:
+//   - gid: the GID for the config
+func WithGID(gid string) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		opt.GID = gid
+		return nil
+	}
+}
+
+// WithMode sets the mode for the config
+// parameters:
+//   - mode: the mode for the config
+func WithMode(mode int64) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		mode := types.FileMode(mode)
+		opt.Mode = &mode
+		return nil
+	}
+}
+
+// Fail is a function that returns an error
+//
+// note: this is useful for when you want to fail the config service config
+// and append the error to the service config error collection
+func Fail(err error) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		return errdefs.NewServiceConfigError("configs", err.Error())
+	}
+}
+
+// Failf is a function that returns an error
+//
+// note: this is useful for when you want to fail the config service config
+// and append the error to the service config error collection
+func Failf(stringFormat string, args ...any) SetConfigServiceConfig {
+	return func(opt *types.ServiceConfigObjConfig) error {
+		return errdefs.NewServiceConfigError("configs", fmt.Sprintf(stringFormat, args...))
+	}
+}
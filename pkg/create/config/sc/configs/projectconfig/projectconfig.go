@@ -0,0 +1,114 @@
+// Package projectconfig provides a set of functions to configure the config for the project
+package projectconfig
+
+import (
+	"fmt"
+
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// SetProjectConfig is a function that sets the config for the project
+type SetProjectConfig func(*types.ConfigObjConfig) error
+
+// WithDriverOptions sets the driver options for the config
+// parameters:
+//   - key: the key for the driver option
+//   - value: the value for the driver option
+func WithDriverOptions(key, value string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		if opt.DriverOpts == nil {
+			opt.DriverOpts = make(map[string]string)
+		}
+		opt.DriverOpts[key] = value
+		return nil
+	}
+}
+
+// WithDriver sets the driver for the config
+// parameters:
+//   - driver: the driver for the config
+func WithDriver(driver string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.Driver = driver
+		return nil
+	}
+}
+
+// WithTemplateDriver sets the template driver for the config
+// parameters:
+//   - templateDriver: the template driver for the config
+func WithTemplateDriver(templateDriver string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.TemplateDriver = templateDriver
+		return nil
+	}
+}
+
+// WithEnvironment sets the environment variable the content of the config is read from
+// parameters:
+//   - environment: the name of the environment variable
+func WithEnvironment(environment string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.Environment = environment
+		return nil
+	}
+}
+
+// WithContent sets the content for the config
+// parameters:
+//   - content: the content for the config
+func WithContent(content string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.Content = content
+		return nil
+	}
+}
+
+// WithName sets the name for the config
+// parameters:
+//   - name: the name for the config
+func WithName(name string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.Name = name
+		return nil
+	}
+}
+
+// WithExternal marks the config as external, it must exist before the project is started
+func WithExternal() SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.External = true
+		return nil
+	}
+}
+
+// WithFile sets the file for the config
+// parameters:
+//   - path: the path for the config
+func WithFile(path string) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		opt.File = path
+		return nil
+	}
+}
+
+// Fail is a function that returns an error
+//
+// note: this is useful for when you want to fail the project config
+// and append the error to the service config error collection
+func Fail(err error) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		return errdefs.NewServiceConfigError("configs", err.Error())
+	}
+}
+
+// Failf is a function that returns an error
+//
+// note: this is useful for when you want to fail the project config
+// and append the error to the service config error collection
+func Failf(stringFormat string, args ...any) SetProjectConfig {
+	return func(opt *types.ConfigObjConfig) error {
+		return errdefs.NewServiceConfigError("configs", fmt.Sprintf(stringFormat, args...))
+	}
+}
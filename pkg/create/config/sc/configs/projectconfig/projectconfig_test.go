@@ -0,0 +1,115 @@
+package projectconfig_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/configs/projectconfig"
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignments(t *testing.T) {
+	tests := []struct {
+		config   *types.ConfigObjConfig
+		setFn    projectconfig.SetProjectConfig
+		field    string
+		wantErr  bool
+		message  string
+		expected any
+	}{
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithTemplateDriver("test"),
+			field:    "TemplateDriver",
+			wantErr:  false,
+			message:  "WithTemplateDriver ok",
+			expected: "test",
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithDriverOptions("test", "test"),
+			field:    "DriverOpts",
+			wantErr:  false,
+			message:  "WithDriverOptions ok",
+			expected: map[string]string{"test": "test"},
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithDriver("test"),
+			field:    "Driver",
+			wantErr:  false,
+			message:  "WithDriver ok",
+			expected: "test",
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithExternal(),
+			field:    "External",
+			wantErr:  false,
+			message:  "WithExternal ok",
+			expected: types.External(true),
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithEnvironment("test"),
+			field:    "Environment",
+			wantErr:  false,
+			message:  "WithEnvironment ok",
+			expected: "test",
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithContent("test"),
+			field:    "Content",
+			wantErr:  false,
+			message:  "WithContent ok",
+			expected: "test",
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithName("test"),
+			field:    "Name",
+			wantErr:  false,
+			message:  "WithName ok",
+			expected: "test",
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.Failf("test error %s", "foo"),
+			field:    "",
+			wantErr:  true,
+			message:  "Failf ok",
+			expected: nil,
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.Fail(errors.New("test error")),
+			field:    "",
+			wantErr:  true,
+			message:  "Fail ok",
+			expected: nil,
+		},
+		{
+			config:   &types.ConfigObjConfig{},
+			setFn:    projectconfig.WithFile("test"),
+			field:    "File",
+			wantErr:  false,
+			message:  "WithFile ok",
+			expected: "test",
+		},
+	}
+
+	for _, test := range tests {
+		err := test.setFn(test.config)
+		if test.wantErr {
+			assert.Error(t, err)
+			assert.True(t, errdefs.IsServiceConfigError(err), "expected service config error")
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, reflect.ValueOf(*test.config).FieldByName(test.field).Interface(), test.message)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/aptd3v/go-contain/pkg/create"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/build"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/configs/configservice"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/deploy"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/secrets/secretservice"
 	"github.com/aptd3v/go-contain/pkg/create/errdefs"
@@ -201,6 +202,33 @@ func WithSecret(setters ...secretservice.SetSecretServiceConfig) create.SetServi
 	}
 }
 
+// WithConfig appends a config to the service
+// parameters:
+//   - setters: the setters for the config
+//
+// configs grants access to configs on a per-service basis.
+func WithConfig(setters ...configservice.SetConfigServiceConfig) create.SetServiceConfig {
+	return func(config *types.ServiceConfig) error {
+		if len(setters) == 0 {
+			return nil
+		}
+		if config.Configs == nil {
+			config.Configs = make([]types.ServiceConfigObjConfig, 0)
+		}
+		obj := types.ServiceConfigObjConfig{}
+		for _, setter := range setters {
+			if setter == nil {
+				continue
+			}
+			if err := setter(&obj); err != nil {
+				return err
+			}
+		}
+		config.Configs = append(config.Configs, obj)
+		return nil
+	}
+}
+
 // Fail is a function that returns an error
 //
 // note: this is useful for when you want to fail the service config
@@ -8,6 +8,7 @@ import (
 	"github.com/aptd3v/go-contain/pkg/create"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/build"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/configs/configservice"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/deploy"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/secrets/secretservice"
 	"github.com/aptd3v/go-contain/pkg/create/errdefs"
@@ -69,6 +70,30 @@ func TestAssignments(t *testing.T) {
 			message:  "WithSecret no setters",
 			expected: ([]types.ServiceSecretConfig)(nil),
 		},
+		{
+			config:   &types.ServiceConfig{},
+			setFn:    sc.WithConfig(configservice.WithSource("nginx_conf"), configservice.WithTarget("/etc/nginx/nginx.conf")),
+			field:    "Configs",
+			wantErr:  false,
+			message:  "WithConfig ok",
+			expected: []types.ServiceConfigObjConfig{{Source: "nginx_conf", Target: "/etc/nginx/nginx.conf"}},
+		},
+		{
+			config:   &types.ServiceConfig{},
+			setFn:    sc.WithConfig(configservice.Fail(errors.New("test error"))),
+			field:    "Configs",
+			wantErr:  true,
+			message:  "WithConfig error setters",
+			expected: ([]types.ServiceConfigObjConfig)(nil),
+		},
+		{
+			config:   &types.ServiceConfig{},
+			setFn:    sc.WithConfig(),
+			field:    "Configs",
+			wantErr:  false,
+			message:  "WithConfig no setters",
+			expected: ([]types.ServiceConfigObjConfig)(nil),
+		},
 		{
 			config:   &types.ServiceConfig{},
 			setFn:    sc.WithBuild(),
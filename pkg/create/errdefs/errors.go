@@ -6,13 +6,14 @@ import (
 )
 
 var (
-	ErrContainerConfig = errors.New("container config has errors")
-	ErrHostConfig      = errors.New("host config has errors")
-	ErrNetworkConfig   = errors.New("network config has errors")
-	ErrPlatformConfig  = errors.New("platform config has errors")
-	ErrServiceConfig   = errors.New("service config has errors")
-	ErrProjectConfig   = errors.New("project config has errors")
-	ErrValidation      = errors.New("container has errors")
+	ErrContainerConfig   = errors.New("container config has errors")
+	ErrHostConfig        = errors.New("host config has errors")
+	ErrNetworkConfig     = errors.New("network config has errors")
+	ErrPlatformConfig    = errors.New("platform config has errors")
+	ErrServiceConfig     = errors.New("service config has errors")
+	ErrProjectConfig     = errors.New("project config has errors")
+	ErrServiceValidation = errors.New("service is invalid")
+	ErrValidation        = errors.New("container has errors")
 )
 
 type ContainerConfigError struct {
@@ -157,3 +158,33 @@ func NewProjectConfigError(field, message string) *ProjectConfigError {
 		Message: message,
 	}
 }
+
+// ServiceValidationError is returned by Project.Validate when a service is inconsistent with the project,
+// e.g. a service references a network that is not declared. It is also a project config error.
+type ServiceValidationError struct {
+	// Service is the name of the invalid service
+	Service string
+	// Field is the compose field of the service, e.g. "networks" or "depends_on"
+	Field   string
+	Message string
+}
+
+func (e *ServiceValidationError) Error() string {
+	return fmt.Sprintf("service %s: %s: %s", e.Service, e.Field, e.Message)
+}
+
+func (e *ServiceValidationError) Unwrap() []error {
+	return []error{ErrServiceValidation, ErrProjectConfig}
+}
+
+func IsServiceValidationError(err error) bool {
+	return errors.Is(err, ErrServiceValidation)
+}
+
+func NewServiceValidationError(service, field, message string) *ServiceValidationError {
+	return &ServiceValidationError{
+		Service: service,
+		Field:   field,
+		Message: message,
+	}
+}
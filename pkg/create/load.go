@@ -0,0 +1,136 @@
+package create
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/cli"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadOptions are the options used to load a project from compose files
+type LoadOptions struct {
+	// Name overrides the project name, by default the name is taken from the compose files,
+	// the COMPOSE_PROJECT_NAME variable or the working directory
+	Name string
+	// WorkingDir is the directory relative paths are resolved against,
+	// defaults to the directory of the first compose file
+	WorkingDir string
+	// EnvFiles are the env files used for interpolation, defaults to the .env file of the working directory
+	EnvFiles []string
+	// Env are additional KEY=VALUE variables used for interpolation, they take precedence over the os environment and env files
+	Env []string
+	// Profiles are the profiles to activate, services of other profiles are disabled.
+	// The profiles are not part of the marshaled project, pass them again to every compose command.
+	Profiles []string
+	// NoOsEnv excludes the os environment from interpolation
+	NoOsEnv bool
+	// NoInterpolate disables the interpolation of variables
+	NoInterpolate bool
+}
+
+// SetLoadOption is a function that sets a load option
+type SetLoadOption func(opt *LoadOptions) error
+
+// LoadProject loads compose files into a project that can be extended like a project created with NewProject.
+// Files are merged in order with the override rules of docker compose, when no file is given
+// compose.yaml or docker-compose.yaml is searched in the working directory and its parents.
+//
+// Variables are interpolated while loading and $$ is unescaped, e.g. "echo $$HOME" is loaded as "echo $HOME".
+// Marshal and Export escape every $ of such a project as $$, docker compose interpolates the marshaled project again.
+// Profiles selected with load.WithProfiles only filter the loaded services, compose commands run on the project
+// must be given the same profiles, e.g. up.WithProfiles, or services of those profiles are not started.
+// parameters:
+//   - ctx: the context of the loader
+//   - paths: the compose files to load
+//   - setters: the load options, see the load package
+//
+// returns a ProjectConfigError if a setter fails or the files cannot be loaded
+//
+// Eg.
+//
+//	project, err := create.LoadProject(ctx, []string{"compose.yaml"}, load.WithProfiles("debug"))
+//	if err != nil {
+//		return err
+//	}
+//	project.WithService("worker", create.NewContainer("worker"))
+func LoadProject(ctx context.Context, paths []string, setters ...SetLoadOption) (*Project, error) {
+	opt := &LoadOptions{}
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(opt); err != nil {
+			return nil, errdefs.NewProjectConfigError("load", err.Error())
+		}
+	}
+	// the order matters, the env files are looked up in the working directory
+	// and variables which are already set are not overridden
+	fns := []cli.ProjectOptionsFn{
+		cli.WithWorkingDirectory(opt.WorkingDir),
+		cli.WithDefaultConfigPath,
+		cli.WithEnv(opt.Env),
+	}
+	if !opt.NoOsEnv {
+		fns = append(fns, cli.WithOsEnv)
+	}
+	fns = append(fns,
+		cli.WithEnvFiles(opt.EnvFiles...),
+		cli.WithDotEnv,
+		cli.WithName(opt.Name),
+		cli.WithInterpolation(!opt.NoInterpolate),
+	)
+	if len(opt.Profiles) > 0 {
+		fns = append(fns, cli.WithProfiles(opt.Profiles))
+	}
+	options, err := cli.NewProjectOptions(paths, fns...)
+	if err != nil {
+		return nil, errdefs.NewProjectConfigError("load", err.Error())
+	}
+	if len(options.ConfigPaths) == 0 {
+		return nil, errdefs.NewProjectConfigError("load", "no compose file found")
+	}
+	project, err := options.LoadProject(ctx)
+	if err != nil {
+		return nil, errdefs.NewProjectConfigError("load", fmt.Sprintf("%s: %s", strings.Join(options.ConfigPaths, ", "), err))
+	}
+	initSections(project)
+	return &Project{wrapped: project, interpolated: !opt.NoInterpolate}, nil
+}
+
+// marshalEscaped marshals the project with every $ of its values escaped as $$
+func marshalEscaped(project *types.Project) ([]byte, error) {
+	model, err := toModel(project)
+	if err != nil {
+		return nil, err
+	}
+	escapeDollars(model)
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(model); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// escapeDollars replaces $ with $$ in the string values of the yaml tree, keys are not interpolated and kept as is
+func escapeDollars(value any) any {
+	switch v := value.(type) {
+	case string:
+		return strings.ReplaceAll(v, "$", "$$")
+	case map[string]any:
+		for key, child := range v {
+			v[key] = escapeDollars(child)
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = escapeDollars(child)
+		}
+	}
+	return value
+}
@@ -0,0 +1,198 @@
+package create
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/loader"
+	"github.com/compose-spec/compose-go/v2/override"
+	"github.com/compose-spec/compose-go/v2/tree"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gopkg.in/yaml.v3"
+)
+
+// MergeOptions are the options used to merge a project into another project
+type MergeOptions struct {
+	// Reset are the paths which are removed from the merged project, the equivalent of the !reset yaml tag
+	Reset []string
+	// Override are the paths whose value is replaced instead of merged, the equivalent of the !override yaml tag
+	Override []string
+}
+
+// MergeOption is a function that sets a merge option
+type MergeOption func(opt *MergeOptions) error
+
+// Merge merges other into the project following the merge rules of docker compose for multiple compose files,
+// as if other was an override file of the project:
+//   - single values such as image or command are replaced by the value of other
+//   - sequences such as ports, volumes, secrets or dns are appended, entries are unique by their target
+//   - mappings such as environment, labels or networks are merged, keys of other override existing keys
+//
+// Services, networks, volumes, secrets and configs which only exist in other are added to the project.
+// The project keeps its name, working directory and environment.
+// When other was loaded with interpolation every $ of the merged project is escaped when marshaled, see LoadProject.
+// parameters:
+//   - other: the project to merge into the project
+//   - setters: the merge options, see the merge package
+//
+// Eg.
+//
+//	base.Merge(prod, merge.WithOverride("services.web.ports"))
+//	if err := base.Validate(); err != nil {
+//		return err
+//	}
+func (p *Project) Merge(other *Project, setters ...MergeOption) *Project {
+	if other == nil {
+		p.errs = append(p.errs, errdefs.NewProjectConfigError("merge", "project to merge is nil"))
+		return p
+	}
+	opt := &MergeOptions{}
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(opt); err != nil {
+			p.errs = append(p.errs, errdefs.NewProjectConfigError("merge", err.Error()))
+			return p
+		}
+	}
+	for _, path := range append(append([]string{}, opt.Reset...), opt.Override...) {
+		if err := validateMergePath(path); err != nil {
+			p.errs = append(p.errs, errdefs.NewProjectConfigError("merge", err.Error()))
+			return p
+		}
+	}
+	merged, err := mergeProjects(p.wrapped, other.wrapped, opt)
+	if err != nil {
+		p.errs = append(p.errs, errdefs.NewProjectConfigError("merge", err.Error()))
+		return p
+	}
+	p.wrapped = merged
+	p.errs = append(p.errs, other.errs...)
+	p.interpolated = p.interpolated || other.interpolated
+	return p
+}
+
+// mergeProjects merges the yaml models of both projects with the override rules of compose-go
+func mergeProjects(base, other *types.Project, opt *MergeOptions) (*types.Project, error) {
+	baseModel, err := toModel(base)
+	if err != nil {
+		return nil, err
+	}
+	otherModel, err := toModel(other)
+	if err != nil {
+		return nil, err
+	}
+	// the name of the override is ignored, the merged project keeps its own name
+	delete(otherModel, "name")
+	for _, path := range opt.Reset {
+		removePaths(baseModel, strings.Split(path, "."), nil)
+		removePaths(otherModel, strings.Split(path, "."), nil)
+	}
+	for _, path := range opt.Override {
+		// only the values which are redefined by the override are replaced
+		removePaths(baseModel, strings.Split(path, "."), otherModel)
+	}
+	model, err := override.Merge(baseModel, otherModel)
+	if err != nil {
+		return nil, err
+	}
+	model, err = override.EnforceUnicity(model)
+	if err != nil {
+		return nil, err
+	}
+	merged, err := fromModel(model, base)
+	if err != nil {
+		return nil, err
+	}
+	merged.ComposeFiles = append(merged.ComposeFiles, other.ComposeFiles...)
+	return merged, nil
+}
+
+// toModel converts a project into the yaml tree used by the compose-go override package
+func toModel(project *types.Project) (map[string]any, error) {
+	data, err := project.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	model := map[string]any{}
+	if err := yaml.Unmarshal(data, &model); err != nil {
+		return nil, err
+	}
+	return model, nil
+}
+
+// fromModel converts the yaml tree back into a project, the fields which are not part of the yaml are taken from base
+func fromModel(model map[string]any, base *types.Project) (*types.Project, error) {
+	project := &types.Project{}
+	if err := loader.Transform(model, project); err != nil {
+		return nil, err
+	}
+	project.WorkingDir = base.WorkingDir
+	project.Environment = base.Environment
+	project.ComposeFiles = append([]string{}, base.ComposeFiles...)
+	project.DisabledServices = base.DisabledServices
+	project.Profiles = base.Profiles
+	initSections(project)
+	return project, nil
+}
+
+// removePaths removes the keys of the model matching the dotted pattern, * matches any key.
+// When only is set a key is removed only when it also exists at the same path in only.
+func removePaths(model map[string]any, pattern []string, only map[string]any) {
+	if len(pattern) == 0 {
+		return
+	}
+	for key, value := range model {
+		if pattern[0] != tree.PathMatchAll && pattern[0] != key {
+			continue
+		}
+		var next map[string]any
+		if only != nil {
+			if _, ok := only[key]; !ok {
+				continue
+			}
+			next, _ = only[key].(map[string]any)
+		}
+		if len(pattern) == 1 {
+			delete(model, key)
+			continue
+		}
+		child, ok := value.(map[string]any)
+		if !ok || (only != nil && next == nil) {
+			continue
+		}
+		removePaths(child, pattern[1:], next)
+	}
+}
+
+// validateMergePath validates a dotted path of the compose model, e.g. services.web.ports
+func validateMergePath(path string) error {
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return fmt.Errorf("invalid merge path %q", path)
+		}
+	}
+	return nil
+}
+
+// initSections sets the sections of the project which are nil to empty values,
+// the With* methods expect them to be set
+func initSections(project *types.Project) {
+	if project.Services == nil {
+		project.Services = types.Services{}
+	}
+	if project.Volumes == nil {
+		project.Volumes = make(types.Volumes)
+	}
+	if project.Networks == nil {
+		project.Networks = make(types.Networks)
+	}
+	if project.Secrets == nil {
+		project.Secrets = make(types.Secrets)
+	}
+	if project.Configs == nil {
+		project.Configs = make(types.Configs)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/configs/projectconfig"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/network"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/secrets/projectsecret"
 	"github.com/aptd3v/go-contain/pkg/create/config/sc/volume"
@@ -23,6 +26,8 @@ import (
 type Project struct {
 	wrapped *types.Project
 	errs    []error
+	// interpolated is set for projects loaded with interpolation, their values are escaped when marshaled
+	interpolated bool
 }
 
 // SetServiceConfig is a function that sets the service config
@@ -44,11 +49,13 @@ func NewProject(name string) *Project {
 	}
 }
 
-// ForEachService iterates over each service in the project and calls the provided function to provide the ability to mutate a service
+// ForEachService iterates over each service in the project in alphabetical order and calls the provided function to provide the ability to mutate a service.
+// The function receives a deep copy of the service, changes including those to maps and pointers such as labels or
+// the healthcheck are stored in the project once the function returns without error.
 // parameters:
 //   - fn: the function to call for each service
 //
-// returns an error if the function returns an error
+// returns an error if the function returns an error, services after the failing service are not visited
 func (p *Project) ForEachService(fn func(name string, service *types.ServiceConfig) error) error {
 	if fn == nil {
 		return errdefs.NewProjectConfigError(p.wrapped.Name, "ForEachService function is nil")
@@ -56,14 +63,144 @@ func (p *Project) ForEachService(fn func(name string, service *types.ServiceConf
 	if p.wrapped.Services == nil {
 		return errdefs.NewProjectConfigError(p.wrapped.Name, "project has no services")
 	}
-	for name, service := range p.wrapped.Services {
+	for _, name := range p.ServiceNames() {
+		service, err := deepCopyService(p.wrapped.Services[name])
+		if err != nil {
+			return errdefs.NewProjectConfigError(p.wrapped.Name, err.Error())
+		}
 		if err := fn(name, &service); err != nil {
 			return err
 		}
+		p.wrapped.Services[name] = service
+	}
+	return nil
+}
+
+// ServiceNames returns the names of the services in the project in alphabetical order
+func (p *Project) ServiceNames() []string {
+	names := make([]string, 0, len(p.wrapped.Services))
+	for name := range p.wrapped.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RemoveService removes a service from the project
+// parameters:
+//   - name: the name of the service
+//
+// returns an error if the service is not found, depends_on references of other services to the service are removed
+func (p *Project) RemoveService(name string) error {
+	if _, ok := p.wrapped.Services[name]; !ok {
+		return errdefs.NewProjectConfigError("project", fmt.Sprintf("service %s not found", name))
+	}
+	delete(p.wrapped.Services, name)
+	for key, other := range p.wrapped.Services {
+		if _, ok := other.DependsOn[name]; !ok {
+			continue
+		}
+		dependsOn := make(types.DependsOnConfig, len(other.DependsOn))
+		for dependency, value := range other.DependsOn {
+			if dependency != name {
+				dependsOn[dependency] = value
+			}
+		}
+		other.DependsOn = dependsOn
+		p.wrapped.Services[key] = other
+	}
+	return nil
+}
+
+// ReplaceService replaces an existing service of the project with a new service
+// parameters:
+//   - name: the name of the service
+//   - service: the container to create the service from
+//   - setters: the setters to apply to the service
+//
+// returns an error if the service is not found or the new service has errors, the existing service is kept on error
+func (p *Project) ReplaceService(name string, service *Container, setters ...SetServiceConfig) error {
+	if _, ok := p.wrapped.Services[name]; !ok {
+		return errdefs.NewProjectConfigError("project", fmt.Sprintf("service %s not found", name))
+	}
+	if err := service.Validate(); err != nil {
+		return errdefs.NewServiceConfigError(name, err.Error())
+	}
+	serv, errs := newServiceConfig(name, service, setters...)
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	p.wrapped.Services[name] = serv
+	return nil
+}
+
+// RenameService renames a service of the project and rewrites the depends_on references of the other services
+// parameters:
+//   - oldName: the current name of the service
+//   - newName: the new name of the service
+//
+// returns an error if the service is not found or a service with the new name already exists
+func (p *Project) RenameService(oldName, newName string) error {
+	service, ok := p.wrapped.Services[oldName]
+	if !ok {
+		return errdefs.NewProjectConfigError("project", fmt.Sprintf("service %s not found", oldName))
+	}
+	if newName == "" {
+		return errdefs.NewProjectConfigError("project", "service name is empty")
+	}
+	if oldName == newName {
+		return nil
+	}
+	if _, ok := p.wrapped.Services[newName]; ok {
+		return errdefs.NewServiceConfigError(newName, "service already exists")
+	}
+	delete(p.wrapped.Services, oldName)
+	service.Name = newName
+	p.wrapped.Services[newName] = service
+	for name, other := range p.wrapped.Services {
+		dependency, ok := other.DependsOn[oldName]
+		if !ok {
+			continue
+		}
+		dependsOn := make(types.DependsOnConfig, len(other.DependsOn))
+		for key, value := range other.DependsOn {
+			dependsOn[key] = value
+		}
+		delete(dependsOn, oldName)
+		dependsOn[newName] = dependency
+		other.DependsOn = dependsOn
+		p.wrapped.Services[name] = other
 	}
 	return nil
 }
 
+// Clone returns a deep copy of the project, changes made to the copy do not affect the project
+func (p *Project) Clone() *Project {
+	wrapped, err := deepCopy(p.wrapped)
+	if err != nil {
+		// the identity transform does not fail, the error is reported by Validate in case it ever does
+		return &Project{wrapped: p.wrapped, errs: append(slices.Clone(p.errs), errdefs.NewProjectConfigError("clone", err.Error())), interpolated: p.interpolated}
+	}
+	return &Project{wrapped: wrapped, errs: slices.Clone(p.errs), interpolated: p.interpolated}
+}
+
+// deepCopy returns a deep copy of the project
+func deepCopy(project *types.Project) (*types.Project, error) {
+	// WithServicesTransform deep copies the project before the services are transformed
+	return project.WithServicesTransform(func(_ string, service types.ServiceConfig) (types.ServiceConfig, error) {
+		return service, nil
+	})
+}
+
+// deepCopyService returns a deep copy of the service
+func deepCopyService(service types.ServiceConfig) (types.ServiceConfig, error) {
+	copied, err := deepCopy(&types.Project{Services: types.Services{service.Name: service}})
+	if err != nil {
+		return types.ServiceConfig{}, err
+	}
+	return copied.Services[service.Name], nil
+}
+
 // GetService returns a service from the project
 // parameters:
 //   - name: the name of the service
@@ -96,6 +233,18 @@ func (p *Project) WithService(name string, service *Container, setters ...SetSer
 		return p
 	}
 
+	serv, errs := newServiceConfig(name, service, setters...)
+	p.errs = append(p.errs, errs...)
+	if p.wrapped.Services == nil {
+		p.wrapped.Services = make(types.Services, 0)
+	}
+	p.wrapped.Services[name] = serv
+	return p
+}
+
+// newServiceConfig converts the container to a service config and applies the setters,
+// it returns the errors of the setters which failed
+func newServiceConfig(name string, service *Container, setters ...SetServiceConfig) (types.ServiceConfig, []error) {
 	config := service.Config
 	//if the container, host, or network config is nil, we need to set it to an empty object to avoid nil pointer dereference
 	if config.Container == nil {
@@ -228,12 +377,13 @@ func (p *Project) WithService(name string, service *Container, setters ...SetSer
 		serv.StopGracePeriod = &t
 	}
 
+	var errs []error
 	for _, setter := range setters {
 		if setter == nil {
 			continue
 		}
 		if err := setter(&serv); err != nil {
-			p.errs = append(p.errs, errdefs.NewServiceConfigError(name, err.Error()))
+			errs = append(errs, errdefs.NewServiceConfigError(name, err.Error()))
 			continue
 		}
 	}
@@ -243,11 +393,7 @@ func (p *Project) WithService(name string, service *Container, setters ...SetSer
 	} else {
 		serv.ContainerName = service.Name
 	}
-	if p.wrapped.Services == nil {
-		p.wrapped.Services = make(types.Services, 0)
-	}
-	p.wrapped.Services[name] = serv
-	return p
+	return serv, errs
 }
 
 // WithVolume defines a new volume in the project
@@ -291,6 +437,27 @@ func (p *Project) WithSecret(key string, setters ...projectsecret.SetProjectSecr
 	return p
 }
 
+// WithConfig defines a new config in the project
+// parameters:
+//   - key: the name of the config, referenced by the services with sc.WithConfig
+//   - setters: the setters to apply to the config
+func (p *Project) WithConfig(key string, setters ...projectconfig.SetProjectConfig) *Project {
+	if p.wrapped.Configs == nil {
+		p.wrapped.Configs = make(types.Configs, 0)
+	}
+	config := types.ConfigObjConfig{}
+	for _, setter := range setters {
+		if setter == nil {
+			continue
+		}
+		if err := setter(&config); err != nil {
+			p.errs = append(p.errs, errdefs.NewProjectConfigError("config", err.Error()))
+		}
+	}
+	p.wrapped.Configs[key] = config
+	return p
+}
+
 // WithNetwork defines a new network in the project
 func (p *Project) WithNetwork(name string, setters ...network.SetNetworkProjectConfig) *Project {
 	if p.wrapped.Networks == nil {
@@ -312,7 +479,14 @@ func (p *Project) WithNetwork(name string, setters ...network.SetNetworkProjectC
 }
 
 // Validate validates the project
-// returns an error if the project has errors
+// Besides the errors of the setters, it checks that:
+//   - every service has an image or a build context
+//   - networks, volumes, secrets and configs used by services are declared in the project
+//   - depends_on references existing services without cycles
+//   - services required to be healthy declare a healthcheck which is not disabled
+//   - host ports and container names are not used by more than one service
+//
+// returns an error if the project has errors, problems of a service are reported as errdefs.ServiceValidationError naming the service and the field
 func (p *Project) Validate() error {
 	errs := []error{}
 	// a basic project must have either a image or a build context
@@ -325,6 +499,7 @@ func (p *Project) Validate() error {
 			continue
 		}
 	}
+	errs = append(errs, p.validateServices()...)
 	if len(p.errs) > 0 {
 		errs = append(errs, errdefs.NewProjectConfigError("project", errors.Join(p.errs...).Error()))
 	}
@@ -334,11 +509,15 @@ func (p *Project) Validate() error {
 	return nil
 }
 
-// Marshal marshals the project to a yaml bytes slice
+// Marshal marshals the project to a yaml bytes slice.
+// Every $ of a project loaded with interpolation is escaped as $$, see LoadProject.
 func (p *Project) Marshal() ([]byte, error) {
 	if err := p.Validate(); err != nil {
 		return nil, err
 	}
+	if p.interpolated {
+		return marshalEscaped(p.wrapped)
+	}
 	return p.wrapped.MarshalYAML()
 }
 
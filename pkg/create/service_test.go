@@ -0,0 +1,139 @@
+package create_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc"
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testProject() *create.Project {
+	return create.NewProject("app").
+		WithService("db", create.NewContainer("db").WithContainerConfig(cc.WithImage("postgres:16"))).
+		WithService("api", create.NewContainer("api").WithContainerConfig(cc.WithImage("api:1")), sc.WithDependsOn("db")).
+		WithService("web", create.NewContainer("web").WithContainerConfig(cc.WithImage("nginx")), sc.WithDependsOn("api"))
+}
+
+func TestForEachService(t *testing.T) {
+	project := testProject()
+	visited := []string{}
+	err := project.ForEachService(func(name string, service *types.ServiceConfig) error {
+		visited = append(visited, name)
+		service.Labels = types.Labels{"tier": name}
+		return nil
+	})
+	assert.NoError(t, err)
+	// services are visited in alphabetical order and changes are stored
+	assert.Equal(t, []string{"api", "db", "web"}, visited)
+	for _, name := range visited {
+		service, err := project.GetService(name)
+		if assert.NoError(t, err) {
+			assert.Equal(t, types.Labels{"tier": name}, service.Labels)
+		}
+	}
+
+	// changes of a failing call are not stored, including changes to maps
+	err = project.ForEachService(func(name string, service *types.ServiceConfig) error {
+		service.Image = "broken"
+		service.Labels["tier"] = "broken"
+		service.DependsOn["cache"] = types.ServiceDependency{Condition: types.ServiceConditionStarted}
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	api, _ := project.GetService("api")
+	assert.Equal(t, "api:1", api.Image)
+	assert.Equal(t, types.Labels{"tier": "api"}, api.Labels)
+	assert.NotContains(t, api.DependsOn, "cache")
+
+	assert.True(t, errdefs.IsProjectConfigError(project.ForEachService(nil)))
+}
+
+func TestServiceNames(t *testing.T) {
+	assert.Equal(t, []string{"api", "db", "web"}, testProject().ServiceNames())
+	assert.Empty(t, create.NewProject("empty").ServiceNames())
+}
+
+func TestRemoveService(t *testing.T) {
+	project := testProject()
+	assert.NoError(t, project.RemoveService("web"))
+	assert.Equal(t, []string{"api", "db"}, project.ServiceNames())
+	assert.True(t, errdefs.IsProjectConfigError(project.RemoveService("web")))
+
+	// depends_on references to the removed service are dropped, the project stays valid
+	project = testProject()
+	clone := project.Clone()
+	assert.NoError(t, clone.RemoveService("api"))
+	web, err := clone.GetService("web")
+	if assert.NoError(t, err) {
+		assert.Empty(t, web.DependsOn)
+	}
+	assert.NoError(t, clone.Validate())
+	web, _ = project.GetService("web")
+	assert.Contains(t, web.DependsOn, "api")
+}
+
+func TestReplaceService(t *testing.T) {
+	project := testProject()
+	err := project.ReplaceService("api", create.NewContainer("api").WithContainerConfig(cc.WithImage("api:2")))
+	assert.NoError(t, err)
+	api, err := project.GetService("api")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "api:2", api.Image)
+		assert.Empty(t, api.DependsOn)
+	}
+
+	err = project.ReplaceService("missing", create.NewContainer("missing"))
+	assert.True(t, errdefs.IsProjectConfigError(err))
+
+	// the existing service is kept when the new service has errors
+	err = project.ReplaceService("api", create.NewContainer("api"), sc.Fail(errors.New("bad service")))
+	assert.True(t, errdefs.IsServiceConfigError(err))
+	api, _ = project.GetService("api")
+	assert.Equal(t, "api:2", api.Image)
+}
+
+func TestRenameService(t *testing.T) {
+	project := testProject()
+	assert.NoError(t, project.RenameService("api", "backend"))
+	assert.Equal(t, []string{"backend", "db", "web"}, project.ServiceNames())
+	backend, err := project.GetService("backend")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "backend", backend.Name)
+		assert.Contains(t, backend.DependsOn, "db")
+	}
+	web, err := project.GetService("web")
+	if assert.NoError(t, err) {
+		assert.Equal(t, types.DependsOnConfig{"backend": {Condition: types.ServiceConditionStarted, Restart: true, Required: true}}, web.DependsOn)
+	}
+
+	assert.True(t, errdefs.IsProjectConfigError(project.RenameService("api", "other")))
+	assert.True(t, errdefs.IsServiceConfigError(project.RenameService("backend", "db")))
+	assert.True(t, errdefs.IsProjectConfigError(project.RenameService("backend", "")))
+}
+
+func TestClone(t *testing.T) {
+	project := testProject()
+	clone := project.Clone()
+	err := clone.ForEachService(func(name string, service *types.ServiceConfig) error {
+		if service.DependsOn != nil {
+			service.DependsOn["extra"] = types.ServiceDependency{Condition: types.ServiceConditionStarted}
+		}
+		service.Image = "changed"
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, clone.RemoveService("web"))
+
+	assert.Equal(t, []string{"api", "db", "web"}, project.ServiceNames())
+	api, _ := project.GetService("api")
+	assert.Equal(t, "api:1", api.Image)
+	assert.NotContains(t, api.DependsOn, "extra")
+	cloned, _ := clone.GetService("api")
+	assert.Equal(t, "changed", cloned.Image)
+	assert.Contains(t, cloned.DependsOn, "extra")
+}
@@ -0,0 +1,237 @@
+package create
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// validateServices checks the references and consistency of the services of the project,
+// it returns a ServiceValidationError for every problem found in the order of the service names
+func (p *Project) validateServices() []error {
+	errs := []error{}
+	names := p.ServiceNames()
+	for _, name := range names {
+		service := p.wrapped.Services[name]
+		errs = append(errs, p.validateReferences(name, service)...)
+		errs = append(errs, p.validateDependencies(name, service)...)
+		errs = append(errs, p.validateHealthchecks(name, service)...)
+	}
+	errs = append(errs, p.validateCycles(names)...)
+	errs = append(errs, p.validateHostPorts(names)...)
+	errs = append(errs, p.validateContainerNames(names)...)
+	return errs
+}
+
+// validateReferences checks that the networks, volumes, secrets and configs used by the service are declared in the project
+func (p *Project) validateReferences(name string, service types.ServiceConfig) []error {
+	errs := []error{}
+	for _, network := range sortedKeys(service.Networks) {
+		// the default network is created by compose when it is not declared
+		if _, ok := p.wrapped.Networks[network]; !ok && network != "default" {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "networks", fmt.Sprintf("network %s is not declared in the project", network)))
+		}
+	}
+	for _, volume := range service.Volumes {
+		if volume.Type != types.VolumeTypeVolume || volume.Source == "" {
+			continue
+		}
+		if _, ok := p.wrapped.Volumes[volume.Source]; !ok {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "volumes", fmt.Sprintf("volume %s is not declared in the project", volume.Source)))
+		}
+	}
+	for _, secret := range service.Secrets {
+		if _, ok := p.wrapped.Secrets[secret.Source]; !ok {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "secrets", fmt.Sprintf("secret %s is not declared in the project", secret.Source)))
+		}
+	}
+	for _, config := range service.Configs {
+		if _, ok := p.wrapped.Configs[config.Source]; !ok {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "configs", fmt.Sprintf("config %s is not declared in the project", config.Source)))
+		}
+	}
+	return errs
+}
+
+// validateDependencies checks that the services the service depends on exist
+func (p *Project) validateDependencies(name string, service types.ServiceConfig) []error {
+	errs := []error{}
+	for _, dependency := range sortedKeys(service.DependsOn) {
+		// compose ignores optional dependencies which are not part of the project
+		if _, ok := p.wrapped.Services[dependency]; !ok && service.DependsOn[dependency].Required {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "depends_on", fmt.Sprintf("service %s does not exist", dependency)))
+		}
+	}
+	return errs
+}
+
+// validateHealthchecks checks that the services the service waits to be healthy declare an enabled healthcheck
+func (p *Project) validateHealthchecks(name string, service types.ServiceConfig) []error {
+	errs := []error{}
+	for _, dependency := range sortedKeys(service.DependsOn) {
+		target, ok := p.wrapped.Services[dependency]
+		if !ok || service.DependsOn[dependency].Condition != types.ServiceConditionHealthy {
+			continue
+		}
+		if target.HealthCheck == nil {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "depends_on", fmt.Sprintf("service %s is required to be healthy but declares no healthcheck", dependency)))
+			continue
+		}
+		if target.HealthCheck.Disable {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "depends_on", fmt.Sprintf("service %s is required to be healthy but its healthcheck is disabled", dependency)))
+		}
+	}
+	return errs
+}
+
+// validateCycles reports every dependency cycle once, starting at the first service of the cycle in names
+func (p *Project) validateCycles(names []string) []error {
+	errs := []error{}
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		state[name] = visiting
+		path = append(path, name)
+		for _, dependency := range sortedKeys(p.wrapped.Services[name].DependsOn) {
+			if _, ok := p.wrapped.Services[dependency]; !ok {
+				continue
+			}
+			switch state[dependency] {
+			case visiting:
+				start := 0
+				for i, n := range path {
+					if n == dependency {
+						start = i
+					}
+				}
+				cycle := append(append([]string{}, path[start:]...), dependency)
+				errs = append(errs, errdefs.NewServiceValidationError(dependency, "depends_on", fmt.Sprintf("dependency cycle %s", strings.Join(cycle, " -> "))))
+			case 0:
+				visit(dependency, path)
+			}
+		}
+		state[name] = done
+	}
+	for _, name := range names {
+		if state[name] == 0 {
+			visit(name, nil)
+		}
+	}
+	return errs
+}
+
+// hostPort is a port published on the host
+type hostPort struct {
+	ip       string
+	port     int
+	protocol string
+}
+
+// conflicts reports whether both ports can not be published at the same time,
+// a port published on all interfaces conflicts with the same port on any interface
+func (h hostPort) conflicts(other hostPort) bool {
+	if h.port != other.port || h.protocol != other.protocol {
+		return false
+	}
+	return h.ip == other.ip || isAnyIP(h.ip) || isAnyIP(other.ip)
+}
+
+func isAnyIP(ip string) bool {
+	return ip == "" || ip == "0.0.0.0" || ip == "::"
+}
+
+// validateHostPorts checks that no host port is published by more than one service
+func (p *Project) validateHostPorts(names []string) []error {
+	errs := []error{}
+	type published struct {
+		service string
+		port    hostPort
+	}
+	seen := []published{}
+	for _, name := range names {
+		reported := map[int]bool{}
+		for _, port := range p.wrapped.Services[name].Ports {
+			ports, ok := publishedPorts(port)
+			if !ok {
+				continue
+			}
+			for _, current := range ports {
+				for _, other := range seen {
+					if other.service == name || !current.conflicts(other.port) || reported[current.port] {
+						continue
+					}
+					reported[current.port] = true
+					errs = append(errs, errdefs.NewServiceValidationError(name, "ports", fmt.Sprintf("host port %d/%s is already published by service %s", current.port, current.protocol, other.service)))
+				}
+			}
+			for _, current := range ports {
+				seen = append(seen, published{service: name, port: current})
+			}
+		}
+	}
+	return errs
+}
+
+// publishedPorts returns the host ports of a port config, ok is false when compose picks a random host port
+// or the published value can not be parsed, e.g. an uninterpolated variable
+func publishedPorts(port types.ServicePortConfig) ([]hostPort, bool) {
+	if port.Published == "" {
+		return nil, false
+	}
+	protocol := port.Protocol
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	first, last, isRange := strings.Cut(port.Published, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return nil, false
+	}
+	end := start
+	if isRange {
+		if end, err = strconv.Atoi(last); err != nil || end < start {
+			return nil, false
+		}
+	}
+	ports := make([]hostPort, 0, end-start+1)
+	for i := start; i <= end; i++ {
+		ports = append(ports, hostPort{ip: port.HostIP, port: i, protocol: protocol})
+	}
+	return ports, true
+}
+
+// validateContainerNames checks that no container name is used by more than one service
+func (p *Project) validateContainerNames(names []string) []error {
+	errs := []error{}
+	owners := map[string]string{}
+	for _, name := range names {
+		containerName := p.wrapped.Services[name].ContainerName
+		if containerName == "" {
+			continue
+		}
+		if owner, ok := owners[containerName]; ok {
+			errs = append(errs, errdefs.NewServiceValidationError(name, "container_name", fmt.Sprintf("container name %s is already used by service %s", containerName, owner)))
+			continue
+		}
+		owners[containerName] = name
+	}
+	return errs
+}
+
+// sortedKeys returns the keys of a map in alphabetical order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,165 @@
+package create_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aptd3v/go-contain/pkg/create"
+	"github.com/aptd3v/go-contain/pkg/create/config/cc"
+	"github.com/aptd3v/go-contain/pkg/create/config/hc"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/configs/configservice"
+	"github.com/aptd3v/go-contain/pkg/create/config/sc/secrets/secretservice"
+	"github.com/aptd3v/go-contain/pkg/create/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// service returns a container running the alpine image, name is the name of the container
+func service(name string, setters ...create.SetHostConfig) *create.Container {
+	return create.NewContainer(name).WithContainerConfig(cc.WithImage("alpine")).WithHostConfig(setters...)
+}
+
+func withNetwork(network string) create.SetServiceConfig {
+	return func(service *types.ServiceConfig) error {
+		if service.Networks == nil {
+			service.Networks = map[string]*types.ServiceNetworkConfig{}
+		}
+		service.Networks[network] = nil
+		return nil
+	}
+}
+
+func withHealthCheck() create.SetServiceConfig {
+	return func(service *types.ServiceConfig) error {
+		service.HealthCheck = &types.HealthCheckConfig{Test: types.HealthCheckTest{"CMD", "true"}}
+		return nil
+	}
+}
+
+func TestValidate(t *testing.T) {
+	type problem struct {
+		service string
+		field   string
+	}
+	tests := []struct {
+		message  string
+		project  *create.Project
+		expected []problem
+	}{
+		{
+			message: "valid project",
+			project: create.NewProject("app").
+				WithNetwork("backend").
+				WithVolume("data").
+				WithSecret("token").
+				WithConfig("motd").
+				WithService("db", service("db", hc.WithRWNamedVolumeMount("data", "/data"), hc.WithPortBindings("tcp", "0.0.0.0", "5432", "5432")),
+					withNetwork("backend"), withHealthCheck()).
+				WithService("api", service("api", hc.WithPortBindings("tcp", "127.0.0.1", "8080", "80")),
+					withNetwork("backend"), withNetwork("default"),
+					sc.WithDependsOnHealthy("db"),
+					sc.WithSecret(secretservice.WithSource("token")),
+					sc.WithConfig(configservice.WithSource("motd"))).
+				WithService("proxy", service("proxy", hc.WithPortBindings("tcp", "127.0.0.2", "8080", "80"))),
+		},
+		{
+			message: "undeclared references",
+			project: create.NewProject("app").
+				WithService("api", service("api", hc.WithRWNamedVolumeMount("data", "/data")),
+					withNetwork("backend"),
+					sc.WithSecret(secretservice.WithSource("token")),
+					sc.WithConfig(configservice.WithSource("motd"))),
+			expected: []problem{{"api", "networks"}, {"api", "volumes"}, {"api", "secrets"}, {"api", "configs"}},
+		},
+		{
+			message: "unknown dependency",
+			project: create.NewProject("app").
+				WithService("api", service("api"), sc.WithDependsOn("db")),
+			expected: []problem{{"api", "depends_on"}},
+		},
+		{
+			message: "dependency cycle",
+			project: create.NewProject("app").
+				WithService("a", service("a"), sc.WithDependsOn("b")).
+				WithService("b", service("b"), sc.WithDependsOn("c")).
+				WithService("c", service("c"), sc.WithDependsOn("a")).
+				WithService("d", service("d"), sc.WithDependsOn("a")),
+			expected: []problem{{"a", "depends_on"}},
+		},
+		{
+			message: "healthy dependency without healthcheck",
+			project: create.NewProject("app").
+				WithService("db", service("db")).
+				WithService("api", service("api"), sc.WithDependsOnHealthy("db")),
+			expected: []problem{{"api", "depends_on"}},
+		},
+		{
+			message: "healthy dependency with disabled healthcheck",
+			project: create.NewProject("app").
+				WithService("db", service("db"), func(service *types.ServiceConfig) error {
+					service.HealthCheck = &types.HealthCheckConfig{Disable: true}
+					return nil
+				}).
+				WithService("api", service("api"), sc.WithDependsOnHealthy("db")),
+			expected: []problem{{"api", "depends_on"}},
+		},
+		{
+			message: "duplicate host ports",
+			project: create.NewProject("app").
+				WithService("a", service("a", hc.WithPortBindings("tcp", "0.0.0.0", "8080", "80"))).
+				WithService("b", service("b", hc.WithPortBindings("tcp", "127.0.0.1", "8080", "8080"))).
+				WithService("c", service("c", hc.WithPortBindings("udp", "0.0.0.0", "8080", "80"))),
+			expected: []problem{{"b", "ports"}},
+		},
+		{
+			message: "duplicate container names",
+			project: create.NewProject("app").
+				WithService("a", service("shared")).
+				WithService("b", service("shared")),
+			expected: []problem{{"b", "container_name"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			err := tt.project.Validate()
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errdefs.IsProjectConfigError(err))
+			problems := []problem{}
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var validationErr *errdefs.ServiceValidationError
+				if errors.As(e, &validationErr) {
+					problems = append(problems, problem{validationErr.Service, validationErr.Field})
+				}
+			}
+			assert.Equal(t, tt.expected, problems)
+		})
+	}
+}
+
+func TestValidateCycleMessage(t *testing.T) {
+	err := create.NewProject("app").
+		WithService("a", service("a"), sc.WithDependsOn("b")).
+		WithService("b", service("b"), sc.WithDependsOn("a")).
+		Validate()
+	assert.True(t, errdefs.IsServiceValidationError(err))
+	assert.EqualError(t, err, "service a: depends_on: dependency cycle a -> b -> a")
+}
+
+func TestValidateHealthcheckMessage(t *testing.T) {
+	err := create.NewProject("app").
+		WithService("db", service("db")).
+		WithService("cache", service("cache"), func(service *types.ServiceConfig) error {
+			service.HealthCheck = &types.HealthCheckConfig{Disable: true}
+			return nil
+		}).
+		WithService("queue", service("queue"), withHealthCheck()).
+		WithService("api", service("api"), sc.WithDependsOnHealthy("db"), sc.WithDependsOnHealthy("cache"), sc.WithDependsOnHealthy("queue")).
+		Validate()
+	assert.True(t, errdefs.IsServiceValidationError(err))
+	assert.EqualError(t, err, "service api: depends_on: service cache is required to be healthy but its healthcheck is disabled\n"+
+		"service api: depends_on: service db is required to be healthy but declares no healthcheck")
+}